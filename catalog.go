@@ -0,0 +1,530 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/olekukonko/tablewriter"
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// defaultCatalogFile is the catalog the catalog subcommands edit unless --file is given.
+const defaultCatalogFile = "data.json"
+
+// catalogUsage describes the catalog subcommands.
+const catalogUsage = `Usage: gosearch catalog <command> [flags]
+
+Commands:
+  list [--tags <tags>]                         List the websites in the catalog
+  show <name>                                  Print a website's entry
+  add --exists <username> [entry flags]        Add a website, verifying it before saving
+  edit <name> --exists <username> [flags]      Change a website's entry, verifying it before saving
+  rm <name>                                    Remove a website
+  suggest <url-template> --exists <username>   Work out how to detect profiles on a new website
+
+Every command except suggest takes --file, the catalog to use (default data.json).
+Run gosearch catalog <command> -h for a command's flags.`
+
+// Catalog manages the website catalog, handling the arguments of the catalog subcommand.
+func Catalog(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, catalogUsage)
+		os.Exit(2)
+	}
+
+	switch args[0] {
+	case "list":
+		CatalogList(args[1:])
+	case "show":
+		CatalogShow(args[1:])
+	case "add":
+		CatalogAdd(args[1:])
+	case "edit":
+		CatalogEdit(args[1:])
+	case "rm":
+		CatalogRemove(args[1:])
+	case "suggest":
+		CatalogSuggest(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown catalog command %q\n\n%s\n", args[0], catalogUsage)
+		os.Exit(2)
+	}
+}
+
+// parseInterspersed parses flags that may appear before or after the positional arguments, returning the positional arguments.
+func parseInterspersed(flags *flag.FlagSet, args []string) []string {
+	flags.Parse(args)
+	var positional []string
+	for flags.NArg() > 0 {
+		positional = append(positional, flags.Arg(0))
+		flags.Parse(flags.Args()[1:])
+	}
+	return positional
+}
+
+// CatalogSuggest proposes a catalog entry for a website by comparing an existing profile with random usernames.
+func CatalogSuggest(args []string) {
+	flags := flag.NewFlagSet("catalog suggest", flag.ExitOnError)
+	exists := flags.String("exists", "", "Username known to have a profile on the website (required)")
+	samples := flags.Int("samples", gosearch.DefaultSuggestSamples, "Number of random usernames to compare with")
+	name := flags.String("name", "", "Website name for the entry (defaults to the URL's host)")
+	var cookies cookieFlag
+	flags.Var(&cookies, "cookie", "Cookie to send as name=value; may be repeated")
+	var netFlags networkFlags
+	netFlags.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gosearch catalog suggest [flags] <url-template> --exists <username>")
+		flags.PrintDefaults()
+	}
+
+	positional := parseInterspersed(flags, args)
+	if len(positional) != 1 || *exists == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	template := positional[0]
+
+	parsed, err := url.Parse(strings.ReplaceAll(template, "{}", "username"))
+	if err != nil || parsed.Host == "" || !strings.Contains(template, "{}") {
+		Red("Error: ", template, " must be an absolute URL with {} in place of the username").Println()
+		os.Exit(1)
+	}
+
+	network, err := netFlags.parse()
+	if err != nil {
+		fmt.Printf("Error configuring network: %v\n", err)
+		os.Exit(1)
+	}
+	defer network.Close()
+
+	website := gosearch.Website{
+		Name:    *name,
+		BaseURL: template,
+		Cookies: cookies,
+	}
+	if website.Name == "" {
+		website.Name = strings.TrimPrefix(parsed.Hostname(), "www.")
+	}
+
+	Yellowf("[*] Comparing %s with %d random usernames on %s...", *exists, *samples, parsed.Hostname()).Println()
+	searcher := gosearch.NewSearcher(gosearch.Data{}, network.Options())
+	suggestion, err := searcher.Suggest(context.Background(), website, *exists, *samples)
+	if err != nil {
+		Red("Error suggesting entry:").Print()
+		White(" " + err.Error()).Println()
+		os.Exit(1)
+	}
+
+	switch {
+	case suggestion.Website.ErrorType == "unknown":
+		Yellow("[?] ", suggestion.Reason, "; the website can only be reported as unverified").Println()
+	case suggestion.Verified:
+		Green("[+] ", suggestion.Reason).Println()
+		Green("[+] Verified: the entry finds ", *exists, " and not a fresh random username").Println()
+	default:
+		Green("[+] ", suggestion.Reason).Println()
+		Red("[-] Not verified: searching with the entry did not reproduce the result; check it with gosearch probe").Println()
+	}
+
+	entry, err := gosearch.FormatWebsite(suggestion.Website)
+	if err != nil {
+		Red("Error encoding entry:").Print()
+		White(" " + err.Error()).Println()
+		os.Exit(1)
+	}
+	fmt.Println()
+	fmt.Print(string(entry))
+}
+
+// CatalogList prints the websites in a catalog.
+func CatalogList(args []string) {
+	flags := flag.NewFlagSet("catalog list", flag.ExitOnError)
+	file := flags.String("file", defaultCatalogFile, "Catalog file")
+	var filters filterFlags
+	filters.register(flags)
+	flags.Parse(args)
+
+	data := loadCatalog(*file)
+	if err := data.CheckFilter(filters.filter()); err != nil {
+		Red("Error filtering websites: ", err.Error()).Println()
+		os.Exit(1)
+	}
+	for _, warning := range data.FilterWarnings(filters.filter()) {
+		Yellow("[!] " + warning).Println()
+	}
+	data = data.Filter(filters.filter())
+	data.Sort()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Name", "Category", "Tags", "NSFW", "Error Type", "URL")
+	for _, website := range data.Websites {
+		nsfw := ""
+		if website.NSFW {
+			nsfw = "yes"
+		}
+		errorType := website.ErrorType
+		if website.Disabled {
+			errorType = "(disabled)"
+		}
+		table.Append(website.Name, website.Category, strings.Join(website.Tags, ", "), nsfw, errorType, website.BaseURL)
+	}
+	if err := table.Render(); err != nil {
+		log.Printf("table render failed: %v", err)
+	}
+	Yellowf("[*] %d website%s in %s", len(data.Websites), plural(len(data.Websites)), *file).Println()
+}
+
+// CatalogShow prints a website's entry as it appears in the catalog.
+func CatalogShow(args []string) {
+	flags := flag.NewFlagSet("catalog show", flag.ExitOnError)
+	file := flags.String("file", defaultCatalogFile, "Catalog file")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gosearch catalog show [flags] <name>")
+		flags.PrintDefaults()
+	}
+
+	positional := parseInterspersed(flags, args)
+	if len(positional) != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	data := loadCatalog(*file)
+	i := catalogIndex(data, positional[0], *file)
+	entry, err := gosearch.FormatWebsite(data.Websites[i])
+	if err != nil {
+		Red("Error encoding entry:").Print()
+		White(" " + err.Error()).Println()
+		os.Exit(1)
+	}
+	fmt.Print(string(entry))
+}
+
+// CatalogAdd adds a website to a catalog once its entry is valid and verified.
+func CatalogAdd(args []string) {
+	flags := flag.NewFlagSet("catalog add", flag.ExitOnError)
+	file := flags.String("file", defaultCatalogFile, "Catalog file")
+	var entry entryFlags
+	entry.register(flags)
+	var verify verifyFlags
+	verify.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gosearch catalog add [flags] --exists <username>")
+		fmt.Fprintln(flags.Output(), "The entry is read from --entry, from the entry flags, or both, with the flags taking precedence.")
+		flags.PrintDefaults()
+	}
+
+	if positional := parseInterspersed(flags, args); len(positional) != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	data := loadCatalog(*file)
+	var website gosearch.Website
+	if err := entry.apply(flags, &website); err != nil {
+		Red("Error: ", err.Error()).Println()
+		os.Exit(1)
+	}
+	if website.Name != "" && data.Index(website.Name) >= 0 {
+		Red("Error: ", *file, " already has a website named ", website.Name, "; use gosearch catalog edit to change it").Println()
+		os.Exit(1)
+	}
+
+	checkEntry(website, verify)
+	data.Websites = append(data.Websites, website)
+	saveCatalog(*file, data)
+	Green("[+] Added ", website.Name, " to ", *file).Println()
+}
+
+// CatalogEdit changes a website's entry in a catalog once the new entry is valid and verified.
+func CatalogEdit(args []string) {
+	flags := flag.NewFlagSet("catalog edit", flag.ExitOnError)
+	file := flags.String("file", defaultCatalogFile, "Catalog file")
+	var entry entryFlags
+	entry.register(flags)
+	var verify verifyFlags
+	verify.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gosearch catalog edit [flags] <name> --exists <username>")
+		fmt.Fprintln(flags.Output(), "--entry replaces the whole entry; the entry flags change single fields, and --name renames the website.")
+		flags.PrintDefaults()
+	}
+
+	positional := parseInterspersed(flags, args)
+	if len(positional) != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	data := loadCatalog(*file)
+	i := catalogIndex(data, positional[0], *file)
+	website := data.Websites[i]
+	if err := entry.apply(flags, &website); err != nil {
+		Red("Error: ", err.Error()).Println()
+		os.Exit(1)
+	}
+	if j := data.Index(website.Name); j >= 0 && j != i {
+		Red("Error: ", *file, " already has a website named ", website.Name).Println()
+		os.Exit(1)
+	}
+
+	checkEntry(website, verify)
+	data.Websites[i] = website
+	saveCatalog(*file, data)
+	Green("[+] Updated ", website.Name, " in ", *file).Println()
+}
+
+// CatalogRemove removes a website from a catalog.
+func CatalogRemove(args []string) {
+	flags := flag.NewFlagSet("catalog rm", flag.ExitOnError)
+	file := flags.String("file", defaultCatalogFile, "Catalog file")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gosearch catalog rm [flags] <name>")
+		flags.PrintDefaults()
+	}
+
+	positional := parseInterspersed(flags, args)
+	if len(positional) != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	data := loadCatalog(*file)
+	i := catalogIndex(data, positional[0], *file)
+	name := data.Websites[i].Name
+	data.Websites = append(data.Websites[:i], data.Websites[i+1:]...)
+	saveCatalog(*file, data)
+	Green("[+] Removed ", name, " from ", *file).Println()
+}
+
+// entryFlags holds the flags building or changing a website entry.
+type entryFlags struct {
+	entry           string
+	name            string
+	url             string
+	probe           string
+	errorType       string
+	errorMsg        string
+	errorCode       int
+	responseURL     string
+	followRedirects bool
+	userAgent       string
+	cookies         cookieFlag
+	category        string
+	tags            listFlag
+	nsfw            bool
+	disabled        bool
+}
+
+// register defines the entry flags on flags.
+func (f *entryFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.entry, "entry", "", "JSON file holding the entry, such as the output of gosearch catalog suggest; - reads standard input")
+	flags.StringVar(&f.name, "name", "", "Website name")
+	flags.StringVar(&f.url, "url", "", "Profile URL template (base_url), with {} in place of the username")
+	flags.StringVar(&f.probe, "probe", "", "URL template requested instead of the profile URL (url_probe)")
+	flags.StringVar(&f.errorType, "error-type", "", "How missing profiles are detected: "+strings.Join(gosearch.ErrorTypes, ", "))
+	flags.StringVar(&f.errorMsg, "error-msg", "", "Text on missing profiles' pages, or on existing ones' for profilePresence (errorMsg)")
+	flags.IntVar(&f.errorCode, "error-code", 0, "Status code of missing profiles for status_code (errorCode)")
+	flags.StringVar(&f.responseURL, "response-url", "", "URL missing profiles are redirected to for response_url")
+	flags.BoolVar(&f.followRedirects, "follow-redirects", false, "Follow redirects (follow_redirects)")
+	flags.StringVar(&f.userAgent, "user-agent", "", "User-Agent to send (user_agent)")
+	flags.Var(&f.cookies, "cookie", "Cookie to send as name=value; may be repeated, replacing the entry's cookies")
+	flags.StringVar(&f.category, "category", "", "Kind of website: "+strings.Join(gosearch.Categories, ", "))
+	flags.Var(&f.tags, "tags", "Comma-separated tags, replacing the entry's tags")
+	flags.BoolVar(&f.nsfw, "nsfw", false, "Mark the website as adult or otherwise sensitive (nsfw)")
+	flags.BoolVar(&f.disabled, "disabled", false, "In an overlay catalog, remove the website of the same name from the catalogs below it")
+}
+
+// apply reads the --entry file into website, if given, then sets the fields whose flags were passed.
+func (f *entryFlags) apply(flags *flag.FlagSet, website *gosearch.Website) error {
+	if f.entry != "" {
+		var content []byte
+		var err error
+		if f.entry == "-" {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			content, err = os.ReadFile(f.entry)
+		}
+		if err != nil {
+			return fmt.Errorf("error reading entry: %w", err)
+		}
+		*website = gosearch.Website{}
+		if err := sonic.Unmarshal(content, website); err != nil {
+			return fmt.Errorf("error parsing entry: %w", err)
+		}
+	}
+
+	flags.Visit(func(flag *flag.Flag) {
+		switch flag.Name {
+		case "name":
+			website.Name = f.name
+		case "url":
+			website.BaseURL = f.url
+		case "probe":
+			website.URLProbe = f.probe
+		case "error-type":
+			website.ErrorType = f.errorType
+		case "error-msg":
+			website.ErrorMsg = f.errorMsg
+		case "error-code":
+			website.ErrorCode = f.errorCode
+		case "response-url":
+			website.ResponseURL = f.responseURL
+		case "follow-redirects":
+			website.FollowRedirects = f.followRedirects
+		case "user-agent":
+			website.UserAgent = f.userAgent
+		case "cookie":
+			website.Cookies = f.cookies
+		case "category":
+			website.Category = f.category
+		case "tags":
+			website.Tags = f.tags
+		case "nsfw":
+			website.NSFW = f.nsfw
+		case "disabled":
+			website.Disabled = f.disabled
+		}
+	})
+	return nil
+}
+
+// verifyFlags holds the flags controlling how an entry is verified before it is saved.
+type verifyFlags struct {
+	exists   string
+	missing  string
+	noVerify bool
+	network  networkFlags
+}
+
+// register defines the verification and network flags on flags.
+func (f *verifyFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.exists, "exists", "", "Username known to have a profile on the website, used to verify the entry")
+	flags.StringVar(&f.missing, "missing", "", "Username known not to have a profile (defaults to a random username; required with --replay)")
+	flags.BoolVar(&f.noVerify, "no-verify", false, "Save the entry without verifying it")
+	f.network.register(flags)
+}
+
+// checkEntry validates a website entry and verifies it by searching for the existing and missing usernames,
+// exiting if either fails. Disabled entries and entries with errorType unknown are only validated.
+func checkEntry(website gosearch.Website, verify verifyFlags) {
+	if err := website.Validate(); err != nil {
+		Red("Error: the entry is not valid:").Println()
+		for _, line := range strings.Split(err.Error(), "\n") {
+			White("    ", line).Println()
+		}
+		os.Exit(1)
+	}
+
+	switch {
+	case website.Disabled:
+		return
+	case verify.noVerify:
+		Yellow("[?] Not verifying ", website.Name, " (--no-verify)").Println()
+		return
+	case website.ErrorType == "unknown":
+		Yellow("[?] Not verifying ", website.Name, ": errorType unknown is always reported as unverified").Println()
+		return
+	case verify.exists == "":
+		Red("Error: pass a username with a profile on ", website.Name, " with --exists, or skip verification with --no-verify").Println()
+		os.Exit(1)
+	case verify.network.replay != "" && verify.missing == "":
+		Red("Error: --replay needs the recorded missing username passed with --missing").Println()
+		os.Exit(1)
+	}
+
+	network, err := verify.network.parse()
+	if err != nil {
+		fmt.Printf("Error configuring network: %v\n", err)
+		os.Exit(1)
+	}
+	defer network.Close()
+
+	missing := verify.missing
+	if missing == "" {
+		missing = gosearch.RandomUsername()
+	}
+	Yellow("[*] Verifying ", website.Name, " with ", verify.exists, " (exists) and ", missing, " (missing)...").Println()
+	searcher := gosearch.NewSearcher(gosearch.Data{Websites: []gosearch.Website{website}}, network.Options())
+	if err := searcher.Verify(context.Background(), website, verify.exists, missing); err != nil {
+		Red("[-] Not verified: ", err.Error()).Println()
+		Red("Error: the entry was not saved; check it with gosearch probe, or skip verification with --no-verify").Println()
+		os.Exit(1)
+	}
+	Green("[+] Verified: the entry finds ", verify.exists, " and not ", missing).Println()
+}
+
+// loadCatalog reads a catalog file, exiting if it cannot be read.
+func loadCatalog(path string) gosearch.Data {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		Red("Error loading catalog:").Print()
+		White(" " + err.Error()).Println()
+		os.Exit(1)
+	}
+	data, err := gosearch.ParseData(content)
+	if err != nil {
+		Red("Error loading catalog:").Print()
+		White(" " + err.Error()).Println()
+		os.Exit(1)
+	}
+	return data
+}
+
+// catalogIndex returns the index of the named website in a catalog, exiting if there is none.
+func catalogIndex(data gosearch.Data, name, path string) int {
+	i := data.Index(name)
+	if i < 0 {
+		Red("Error: ", path, " has no website named ", name).Println()
+		os.Exit(1)
+	}
+	return i
+}
+
+// saveCatalog validates a catalog and writes it sorted and formatted, exiting if either fails.
+// The file is replaced in one step so an interrupted save cannot leave it half written.
+func saveCatalog(path string, data gosearch.Data) {
+	err := data.Validate()
+	if err == nil {
+		err = writeCatalog(path, data)
+	}
+	if err != nil {
+		Red("Error saving catalog:").Println()
+		for _, line := range strings.Split(err.Error(), "\n") {
+			White("    ", line).Println()
+		}
+		os.Exit(1)
+	}
+}
+
+// writeCatalog formats a catalog and replaces the file with it.
+func writeCatalog(path string, data gosearch.Data) error {
+	content, err := gosearch.FormatData(data)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".catalog-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
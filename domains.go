@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// domainFlags holds the command-line flags controlling which domains are checked and how.
+// They are shared by the search command and the serve subcommand.
+type domainFlags struct {
+	tlds        listFlag
+	tldsFile    string
+	addTLDs     listFlag
+	addTLDsFile string
+	timeout     time.Duration
+	concurrency int
+}
+
+// register defines the domain flags on flags.
+func (f *domainFlags) register(flags *flag.FlagSet) {
+	flags.Var(&f.tlds, "tlds", "Check domains under these TLDs instead of the defaults (comma-separated; may be repeated)")
+	flags.StringVar(&f.tldsFile, "tlds-file", "", "File of TLDs, one per line, to check instead of the defaults")
+	flags.Var(&f.addTLDs, "add-tlds", "Also check domains under these TLDs, such as ccTLDs (comma-separated; may be repeated)")
+	flags.StringVar(&f.addTLDsFile, "add-tlds-file", "", "File of TLDs, one per line, to check in addition to the others")
+	flags.DurationVar(&f.timeout, "domain-timeout", gosearch.DefaultDomainTimeout, "Time limit for checking a single domain")
+	flags.IntVar(&f.concurrency, "domain-concurrency", gosearch.DefaultDomainConcurrency, "Number of domains checked at once")
+}
+
+// Domains holds the parsed domain settings.
+type Domains struct {
+	TLDs    []string // TLDs to check, or nil for the defaults
+	Custom  bool     // Whether the TLDs differ from the defaults
+	timeout time.Duration
+	workers int
+}
+
+// parse validates the domain flags and builds the TLD list: --tlds and --tlds-file replace the defaults,
+// then --add-tlds and --add-tlds-file extend the result.
+func (f *domainFlags) parse() (Domains, error) {
+	if f.concurrency <= 0 {
+		return Domains{}, fmt.Errorf("--domain-concurrency must be at least 1")
+	}
+	domains := Domains{timeout: f.timeout, workers: f.concurrency}
+
+	base, err := f.tldList(f.tlds, f.tldsFile)
+	if err != nil {
+		return Domains{}, err
+	}
+	extra, err := f.tldList(f.addTLDs, f.addTLDsFile)
+	if err != nil {
+		return Domains{}, err
+	}
+	if len(base) == 0 && len(extra) == 0 {
+		return domains, nil
+	}
+	if len(base) == 0 {
+		base = gosearch.DefaultTLDs
+	}
+
+	domains.TLDs, _ = gosearch.ParseTLDs(append(append([]string{}, base...), extra...))
+	domains.Custom = true
+	return domains, nil
+}
+
+// tldList combines TLDs given on the command line with those read from a file, if any.
+func (f *domainFlags) tldList(values []string, file string) ([]string, error) {
+	tlds, err := gosearch.ParseTLDs(values)
+	if err != nil {
+		return nil, err
+	}
+	if file != "" {
+		fromFile, err := gosearch.LoadTLDs(file)
+		if err != nil {
+			return nil, err
+		}
+		tlds = append(tlds, fromFile...)
+	}
+	return tlds, nil
+}
+
+// apply sets the domain options on options.
+func (d Domains) apply(options *gosearch.Options) {
+	options.TLDs = d.TLDs
+	options.DomainTimeout = d.timeout
+	options.DomainConcurrency = d.workers
+}
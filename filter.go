@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// listFlag collects comma-separated values from a flag that may be repeated.
+type listFlag []string
+
+// String returns the collected values.
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set appends the comma-separated values, ignoring empty ones.
+func (l *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l = append(*l, item)
+		}
+	}
+	return nil
+}
+
+// filterFlags holds the command-line flags choosing which websites are searched.
+type filterFlags struct {
+	sites        listFlag
+	excludeSites listFlag
+	tags         listFlag
+	excludeTags  listFlag
+	includeNSFW  bool
+}
+
+// register defines the filter flags on flags.
+func (f *filterFlags) register(flags *flag.FlagSet) {
+	flags.Var(&f.sites, "sites", "Only search these websites (comma-separated names; may be repeated)")
+	flags.Var(&f.excludeSites, "exclude-sites", "Never search these websites (comma-separated names; may be repeated)")
+	flags.Var(&f.tags, "tags", "Only search websites with one of these categories or tags (comma-separated; may be repeated)")
+	flags.Var(&f.excludeTags, "exclude-tags", "Never search websites with any of these categories or tags (comma-separated; may be repeated)")
+	flags.BoolVar(&f.includeNSFW, "include-nsfw", false, "Also search adult and otherwise sensitive websites; their results are marked [NSFW]")
+}
+
+// filter returns the filter described by the flags.
+func (f *filterFlags) filter() gosearch.Filter {
+	return gosearch.Filter{
+		Sites:        f.sites,
+		ExcludeSites: f.excludeSites,
+		Tags:         f.tags,
+		ExcludeTags:  f.excludeTags,
+		IncludeNSFW:  f.includeNSFW,
+	}
+}
+
+// resultLabel returns the labels printed after a result: [NSFW] for websites flagged nsfw, and the catalog
+// the website came from unless it is the upstream catalog.
+func resultLabel(result gosearch.Result) string {
+	var label string
+	if result.NSFW {
+		label += " [NSFW]"
+	}
+	if result.Catalog != "" && result.Catalog != gosearch.UpstreamCatalog {
+		label += " (catalog: " + result.Catalog + ")"
+	}
+	return label
+}
+
+// catalogFlags holds the command-line flags choosing the catalogs websites are loaded from.
+type catalogFlags struct {
+	overlays      listFlag
+	noUpstream    bool
+	keys          listFlag
+	allowUnsigned bool
+}
+
+// register defines the catalog flags on flags.
+func (f *catalogFlags) register(flags *flag.FlagSet) {
+	flags.Var(&f.overlays, "catalog", "Overlay catalog file or URL merged on top of the upstream catalog; may be repeated, later catalogs win")
+	flags.BoolVar(&f.noUpstream, "no-upstream", false, "Do not load the upstream catalog; only search the --catalog overlays")
+	flags.Var(&f.keys, "catalog-key", "Minisign public key, or .pub file, also trusted to sign remote catalogs; may be repeated")
+	flags.BoolVar(&f.allowUnsigned, "allow-unsigned-catalogs", false, "Load remote catalogs that are not signed by a trusted key")
+}
+
+// trust returns the keys trusted to sign remote catalogs: the compiled-in upstream key and any --catalog-key.
+func (f *catalogFlags) trust() (gosearch.CatalogTrust, error) {
+	trust := gosearch.DefaultCatalogTrust()
+	trust.AllowUnsigned = f.allowUnsigned
+	for _, value := range f.keys {
+		key, err := gosearch.LoadPublicKey(value)
+		if err != nil {
+			return gosearch.CatalogTrust{}, err
+		}
+		trust.Keys = append(trust.Keys, key)
+	}
+	return trust, nil
+}
+
+// load loads the upstream catalog, unless disabled, and merges the overlay catalogs on top of it in order.
+// Remote catalogs must be signed by a trusted key unless unsigned catalogs are allowed. Overlays are validated,
+// since unlike the upstream catalog they are not checked before being published.
+func (f *catalogFlags) load(transport http.RoundTripper) (gosearch.Data, []string, error) {
+	if f.noUpstream && len(f.overlays) == 0 {
+		return gosearch.Data{}, nil, errors.New("--no-upstream needs at least one --catalog")
+	}
+	trust, err := f.trust()
+	if err != nil {
+		return gosearch.Data{}, nil, err
+	}
+
+	var catalogs []gosearch.Data
+	if !f.noUpstream {
+		data, err := LoadData(transport, trust)
+		if err != nil {
+			return gosearch.Data{}, nil, unsignedHint(err)
+		}
+		catalogs = append(catalogs, data)
+	}
+	for _, source := range f.overlays {
+		overlay, err := gosearch.LoadCatalog(&http.Client{Transport: transport}, source, trust)
+		if err != nil {
+			return gosearch.Data{}, nil, unsignedHint(err)
+		}
+		if err := overlay.Validate(); err != nil {
+			return gosearch.Data{}, nil, fmt.Errorf("%s is not valid:\n%w", source, err)
+		}
+		catalogs = append(catalogs, overlay)
+	}
+
+	names := make([]string, len(catalogs))
+	for i, catalog := range catalogs {
+		names[i] = catalog.Name
+	}
+	return gosearch.MergeData(catalogs...), names, nil
+}
+
+// unsignedHint adds how to load an unsigned catalog to errors about one.
+func unsignedHint(err error) error {
+	if errors.Is(err, gosearch.ErrUnsigned) {
+		return fmt.Errorf("%w\nTrust the key it is signed with using --catalog-key, or load it anyway with --allow-unsigned-catalogs", err)
+	}
+	return err
+}
@@ -4,8 +4,8 @@ go 1.24.1
 
 require (
 	github.com/bytedance/sonic v1.13.2
-	github.com/ibnaleem/gobreach v0.0.0-20250116204935-7ddbbc80aa72
 	github.com/inancgumus/screen v0.0.0-20190314163918-06e984b86ed3
+	github.com/olekukonko/tablewriter v1.0.6-0.20250516170326-571d727fad4b
 )
 
 require (
@@ -15,7 +15,6 @@ require (
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/olekukonko/errors v1.1.0 // indirect
 	github.com/olekukonko/ll v0.0.8-0.20250516010636-22ea57d81985 // indirect
-	github.com/olekukonko/tablewriter v1.0.6-0.20250516170326-571d727fad4b // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 )
 
@@ -26,7 +25,7 @@ require (
 	github.com/klauspost/cpuid/v2 v2.2.10 // indirect
 	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
 	golang.org/x/arch v0.15.0 // indirect
-	golang.org/x/crypto v0.36.0 // indirect
+	golang.org/x/crypto v0.36.0
 	golang.org/x/sys v0.33.0 // indirect
 	golang.org/x/term v0.30.0 // indirect
 )
@@ -3,30 +3,17 @@
 package main
 
 import (
-	"compress/gzip"
-	"compress/zlib"
-	"crypto/tls"
-	"errors"
+	"context"
 	"flag"
 	"fmt"
-	"github.com/olekukonko/tablewriter/renderer"
 	"io"
-	"log"
-	"net"
 	"net/http"
 	"os"
-	"strconv"
 	"strings"
-	"sync"
-	"sync/atomic"
 	"time"
 
-	"github.com/andybalholm/brotli"
-	"github.com/bytedance/sonic"
-	"github.com/ibnaleem/gobreach"
 	"github.com/inancgumus/screen"
-	"github.com/olekukonko/tablewriter"
-	"github.com/olekukonko/tablewriter/tw"
+	"github.com/tkerby/gosearch/pkg/gosearch"
 )
 
 // GoSearch ASCII logo displayed at program start.
@@ -42,40 +29,13 @@ const ASCII = `
 
 `
 
-// User-Agent header used in HTTP requests to mimic a browser.
-const DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:140.0) Gecko/20100101 Firefox/140.0"
-
 // GoSearch version number.
 const VERSION = "v1.0.0"
 
 var (
 
-	// tlsConfig defines the TLS configuration for secure HTTP requests.
-	tlsConfig = &tls.Config{
-		MinVersion: tls.VersionTLS12, // Minimum TLS version
-		CipherSuites: []uint16{ // Supported cipher suites
-			tls.TLS_AES_128_GCM_SHA256,
-			tls.TLS_AES_256_GCM_SHA384,
-			tls.TLS_CHACHA20_POLY1305_SHA256,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
-			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
-			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
-			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
-		},
-		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}, // Preferred elliptic curves
-		NextProtos:       []string{"http/1.1"},                                    // Supported protocols
-	}
-
-	// count tracks the number of found profiles using atomic operations for thread safety.
-	count atomic.Uint32
-
 	// CurrentTheme holds the active color theme for terminal output.
 	CurrentTheme = DarkTheme
-
-	// file mutext
-	mu sync.Mutex
 )
 
 // Theme defines color codes for terminal output styling.
@@ -129,65 +89,6 @@ func init() {
 	CurrentTheme = detectTheme()
 }
 
-// Website represents a website configuration for searching usernames.
-type Website struct {
-	Name            string   `json:"name"`                   // Website name
-	BaseURL         string   `json:"base_url"`               // Base URL template
-	URLProbe        string   `json:"url_probe,omitempty"`    // Optional probe URL
-	FollowRedirects bool     `json:"follow_redirects"`       // Whether to follow HTTP redirects
-	UserAgent       string   `json:"user_agent,omitempty"`   // Custom User-Agent, if any
-	ErrorType       string   `json:"errorType"`              // Type of error checking
-	ErrorMsg        string   `json:"errorMsg,omitempty"`     // Expected error message for non-existent profiles
-	ErrorCode       int      `json:"errorCode,omitempty"`    // Expected HTTP status code for non-existent profiles
-	ResponseURL     string   `json:"response_url,omitempty"` // Expected response URL for existing profiles
-	Cookies         []Cookie `json:"cookies,omitempty"`      // Cookies to include in requests
-}
-
-// Data holds the list of websites to search.
-type Data struct {
-	Websites []Website `json:"websites"` // List of website configurations
-}
-
-// Cookie represents an HTTP cookie.
-type Cookie struct {
-	Name  string `json:"name"`  // Cookie name
-	Value string `json:"value"` // Cookie value
-}
-
-// Stealer represents data from an info-stealer compromise.
-type Stealer struct {
-	TotalCorporateServices int         `json:"total_corporate_services"` // Number of corporate services compromised
-	TotalUserServices      int         `json:"total_user_services"`      // Number of user services compromised
-	DateCompromised        string      `json:"date_compromised"`         // Date of compromise
-	StealerFamily          string      `json:"stealer_family"`           // Type of stealer malware
-	ComputerName           string      `json:"computer_name"`            // Name of compromised computer
-	OperatingSystem        string      `json:"operating_system"`         // Operating system of compromised computer
-	MalwarePath            string      `json:"malware_path"`             // Path of malware on compromised system
-	Antiviruses            interface{} `json:"antiviruses"`              // Antivirus software detected
-	IP                     string      `json:"ip"`                       // IP address of compromised system
-	TopPasswords           []string    `json:"top_passwords"`            // Commonly used passwords
-	TopLogins              []string    `json:"top_logins"`               // Commonly used logins
-}
-
-// HudsonRockResponse represents the response from HudsonRock's API.
-type HudsonRockResponse struct {
-	Message  string    `json:"message"`  // Response message
-	Stealers []Stealer `json:"stealers"` // List of stealer data
-}
-
-// WeakpassResponse represents the response from Weakpass API for hash cracking.
-type WeakpassResponse struct {
-	Type string `json:"type"` // Hash type
-	Hash string `json:"hash"` // Hash value
-	Pass string `json:"pass"` // Cracked password
-}
-
-// ProxyNova represents the response from ProxyNova API for compromised passwords.
-type ProxyNova struct {
-	Count int      `json:"count"` // Number of compromised credentials
-	Lines []string `json:"lines"` // List of credential pairs
-}
-
 // Color represents a colored string for terminal output.
 type Color string
 
@@ -218,9 +119,26 @@ func (c Color) Fprintln(w io.Writer) {
 
 // main is the entry point of the program, handling command-line arguments and orchestrating searches.
 func main() {
-	// Variables to store username and API key
+	// Run the HTTP API server if requested
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		Serve(os.Args[2:])
+		return
+	}
+
+	// Probe a single URL if requested
+	if len(os.Args) > 1 && os.Args[1] == "probe" {
+		Probe(os.Args[2:])
+		return
+	}
+
+	// Manage the website catalog if requested
+	if len(os.Args) > 1 && os.Args[1] == "catalog" {
+		Catalog(os.Args[2:])
+		return
+	}
+
+	// Variable to store username
 	var username string
-	var apikey string
 
 	// Define command-line flags
 	usernameFlag := flag.String("u", "", "Username to search")
@@ -228,6 +146,20 @@ func main() {
 	noFalsePositivesFlag := flag.Bool("no-false-positives", false, "Do not show false positives")
 	breachDirectoryAPIKey := flag.String("b", "", "Search Breach Directory with an API Key")
 	breachDirectoryAPIKeyLong := flag.String("breach-directory", "", "Search Breach Directory with an API Key")
+	var webhooks webhookFlag
+	flag.Var(&webhooks, "webhook", "Post results to a webhook URL, optionally prefixed with a format (json=, slack=, discord=, mattermost=); may be repeated")
+	notifyMode := flag.String("notify", NotifySummary, "Webhook events to post: summary, findings or all")
+	var netFlags networkFlags
+	netFlags.register(flag.CommandLine)
+	var filters filterFlags
+	filters.register(flag.CommandLine)
+	var catalogs catalogFlags
+	catalogs.register(flag.CommandLine)
+	var domainOptions domainFlags
+	domainOptions.register(flag.CommandLine)
+	outputFormat := flag.String("format", FormatText, "Output formats, comma-separated: text, json, csv or stdout")
+	outputDir := flag.String("output-dir", ".", "Directory to write output files to")
+	outputTemplate := flag.String("output", DefaultOutputTemplate, "Output filename template; {username}, {date} and {time} are replaced")
 
 	// Parse command-line flags
 	flag.Parse()
@@ -246,1015 +178,173 @@ func main() {
 		}
 	}
 
-	// Delete any existing output file for the username
-	DeleteOldFile(username)
-	// Initialize a wait group for concurrent operations
-	var wg sync.WaitGroup
-
-	// Load website data from JSON
-	data, err := UnmarshalJSON()
+	// Route every request through the configured proxies
+	network, err := netFlags.parse()
 	if err != nil {
-		fmt.Printf("Error unmarshalling json: %v\n", err)
+		fmt.Printf("Error configuring network: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Clear the terminal screen
-	screen.Clear()
-	// Display ASCII logo and version
-	fmt.Print(ASCII)
-	fmt.Println(VERSION)
-	// Print separator line
-	fmt.Println(strings.Repeat("⎯", 85))
-	// Display search parameters
-	fmt.Println(":: Username                              : ", username)
-	fmt.Println(":: Websites                              : ", len(data.Websites))
-
-	// Display false positives setting if enabled
-	if *noFalsePositivesFlag {
-		fmt.Println(":: No False Positives                    : ", *noFalsePositivesFlag)
-	}
-
-	// Print separator line
-	fmt.Println(strings.Repeat("⎯", 85))
-	fmt.Println()
-
-	// Warn about false positives if not disabled
-	if !*noFalsePositivesFlag {
-		fmt.Println("[!] A yellow link indicates that I was unable to verify whether the username exists on the platform.")
-	}
-
-	// Record start time for performance measurement
-	start := time.Now()
-
-	// Start searching websites concurrently
-	wg.Add(len(data.Websites))
-	go Search(data, username, *noFalsePositivesFlag, &wg)
-	wg.Wait()
-
-	fmt.Println()
-	fmt.Println()
-
-	// Search HudsonRock's database
-	wg.Add(1)
-	WriteToFile(username, strings.Repeat("⎯", 85))
-	Yellow("[*] Searching HudsonRock's Cybercrime Intelligence Database...").Println()
-	go HudsonRock(username, &wg)
-	wg.Wait()
-
-	// Search Breach Directory if API key is provided
-	if *breachDirectoryAPIKey != "" || *breachDirectoryAPIKeyLong != "" {
-		if *breachDirectoryAPIKey != "" {
-			apikey = *breachDirectoryAPIKey
-		} else {
-			apikey = *breachDirectoryAPIKeyLong
-		}
-
-		fmt.Println()
-		fmt.Println()
-
-		//fmt.Println(strings.Repeat("⎯", 85))
-		//strings.Repeat("⎯", 85)
-		wg.Add(1)
-		go SearchBreachDirectory(username, apikey, &wg)
-		wg.Wait()
-	}
-
-	fmt.Println()
-	fmt.Println()
-
-	// Search ProxyNova for compromised passwords
-	wg.Add(1)
-	// fmt.Println(strings.Repeat("⎯", 85))
-	WriteToFile(username, strings.Repeat("⎯", 85))
-	go SearchProxyNova(username, &wg)
-	wg.Wait()
-
-	fmt.Println()
-	fmt.Println()
-
-	// Search for domains associated with the username
-	domains := BuildDomains(username)
-	//fmt.Println(strings.Repeat("⎯", 85))
-	wg.Add(1)
-	go SearchDomains(username, domains, &wg)
-	wg.Wait()
-
-	fmt.Println()
-	fmt.Println()
-
-	// Calculate and display elapsed time
-	elapsed := time.Since(start)
-
-	table := tablewriter.NewTable(os.Stdout, tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Borders: tw.BorderNone})))
-	table.Append(Bold("Number of profiles found"), Red(count.Load()))
-	table.Append(Bold("Total time taken"), Green(elapsed))
-	if err := table.Render(); err != nil {
-		log.Printf("table render failed: %v", err)
-	}
-	// fmt.Println(strings.Repeat("⎯", 85))
-
-	WriteToFile(username, ":: Number of profiles found              : "+strconv.Itoa(int(count.Load())))
-	WriteToFile(username, ":: Total time taken                      : "+elapsed.String())
-}
-
-// UnmarshalJSON fetches and parses the website configuration from a remote JSON file.
-func UnmarshalJSON() (Data, error) {
-	// GoSearch relies on data.json to determine the websites to search for.
-	// Instead of forcing users to manually download the data.json file, we will fetch the latest version from the repository.
-	// Therefore, we will do the following:
-	// 1. Delete the existing data.json file if it exists as it will be outdated in the future
-	// 2. Read the latest data.json file from the repository
-	// Bonus: it does not download the data.json file, it just reads it from the repository.
-
-	// Delete existing data.json file
-	err := os.Remove("data.json")
-	if err != nil && !os.IsNotExist(err) {
-		return Data{}, fmt.Errorf("error deleting old data.json: %w", err)
-	}
-
-	// Fetch JSON from repository
-	url := "https://raw.githubusercontent.com/ibnaleem/gosearch/refs/heads/main/data.json"
-	resp, err := http.Get(url)
-	if err != nil {
-		return Data{}, fmt.Errorf("error downloading data.json: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Check HTTP status
-	if resp.StatusCode != http.StatusOK {
-		return Data{}, fmt.Errorf("failed to download data.json, status code: %d", resp.StatusCode)
-	}
-
-	// Read response body
-	jsonData, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return Data{}, fmt.Errorf("error reading downloaded content: %w", err)
-	}
-
-	// Unmarshal JSON into Data struct
-	var data Data
-	err = sonic.Unmarshal(jsonData, &data)
-	if err != nil {
-		return Data{}, fmt.Errorf("error unmarshalling JSON: %w", err)
-	}
-
-	return data, nil
-}
-
-// WriteToFile appends content to a file named after the username.
-func WriteToFile(username string, content string) {
-	mu.Lock()
-	defer mu.Unlock()
-
-	// Construct filename
-	filename := fmt.Sprintf("%s.txt", username)
-
-	// Open file in append mode, create if it doesn't exist
-	f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY|os.O_CREATE, os.ModePerm)
+	// Choose the domains to check
+	domains, err := domainOptions.parse()
 	if err != nil {
-		log.Fatal(err)
-	}
-	defer f.Close()
-
-	// Write content to file
-	if _, err = f.WriteString(content); err != nil {
-		log.Fatal(err)
-	}
-}
-
-// BuildURL constructs a URL by replacing the placeholder with the username.
-func BuildURL(baseURL, username string) string {
-	return strings.Replace(baseURL, "{}", username, 1)
-}
-
-// HudsonRock searches HudsonRock's database for info-stealer compromises.
-func HudsonRock(username string, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	// Construct API URL
-	url := fmt.Sprintf("https://cavalier.hudsonrock.com/api/json/v2/osint-tools/search-by-username?username=%s", username)
-
-	// Send HTTP request
-	resp, err := http.Get(url)
-	if err != nil {
-		Redf("Error fetching HudsonRock data:").Print()
-		White(" " + err.Error()).Println()
-		return
+		fmt.Printf("Error configuring domains: %v\n", err)
+		os.Exit(1)
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Print results to the terminal and write them to every output sink
+	sinks, err := NewSinks(*outputFormat, *outputDir, *outputTemplate, username)
 	if err != nil {
-		Red("Error reading response:").Print()
-		White(" " + err.Error()).Println()
-		return
-	}
-
-	// Parse JSON response
-	var response HudsonRockResponse
-	if err := sonic.Unmarshal(body, &response); err != nil {
-		Red("Error parsing JSON:").Print()
-		White(" " + err.Error()).Println()
-		return
-	}
-
-	// Check if no compromises were found
-	if response.Message == "This username is not associated with a computer infected by an info-stealer. Visit https://www.hudsonrock.com/free-tools to discover additional free tools and Infostealers related data." {
-		Green("✓ No info-stealer association found").Println()
-		WriteToFile(username, ":: No info-stealer association found")
-		return
-	}
-
-	// Display warning for detected compromises
-	Red("‼ Info-stealer compromise detected").Println()
-	Yellow("  All credentials on this computer may be exposed").Println()
-
-	// Initialize table for terminal output
-	table := tablewriter.NewTable(os.Stdout, tablewriter.WithHeaderConfig(tw.CellConfig{
-		Formatting: tw.CellFormatting{
-			AutoFormat: tw.Off,
-		},
-	}))
-	table.Header([]any{
-		Blue("#"),
-		Blue("Stealer"),
-		Blue("Date"),
-		Blue("Computer"),
-		Blue("Passwords"),
-	})
-
-	// Buffer for file output
-	var fileContent strings.Builder
-
-	// Process each stealer entry
-	for i, stealer := range response.Stealers {
-		// Format antiviruses
-		var avs string
-		switch v := stealer.Antiviruses.(type) {
-		case string:
-			avs = v
-		case []interface{}:
-			parts := make([]string, len(v))
-			for i, av := range v {
-				parts[i] = fmt.Sprint(av)
-			}
-			avs = strings.Join(parts, ", ")
-		}
-
-		// Highlight computer name if valid
-		computerName := stealer.ComputerName
-		if !strings.EqualFold(strings.TrimSpace(computerName), "Not Found") {
-			computerName = Red(computerName).String()
-		}
-		// Add to terminal table
-		table.Append([]string{
-			fmt.Sprintf("%d", i+1),
-			stealer.StealerFamily,
-			formatStealerDate(stealer.DateCompromised),
-			computerName,
-			strings.Join(stealer.TopPasswords, "\n"),
-		})
-
-		// Add to file content
-		fileContent.WriteString(fmt.Sprintf("[-] Stealer #%d\n", i+1))
-		fileContent.WriteString(fmt.Sprintf(":: Family: %s\n", stealer.StealerFamily))
-		fileContent.WriteString(fmt.Sprintf(":: Date: %s\n", stealer.DateCompromised))
-		fileContent.WriteString(fmt.Sprintf(":: Computer: %s\n", stealer.ComputerName))
-		fileContent.WriteString(fmt.Sprintf(":: OS: %s\n", stealer.OperatingSystem))
-		fileContent.WriteString(fmt.Sprintf(":: Path: %s\n", stealer.MalwarePath))
-		fileContent.WriteString(fmt.Sprintf(":: AV: %s\n", avs))
-		fileContent.WriteString(fmt.Sprintf(":: IP: %s\n", stealer.IP))
-
-		fileContent.WriteString(":: Passwords:\n")
-		for _, p := range stealer.TopPasswords {
-			fileContent.WriteString(fmt.Sprintf("   %s\n", p))
-		}
-
-		fileContent.WriteString(":: Logins:\n")
-		for _, l := range stealer.TopLogins {
-			fileContent.WriteString(fmt.Sprintf("   %s\n", l))
-		}
-		fileContent.WriteString("\n")
-	}
-
-	// Render table to terminal
-	if err := table.Render(); err != nil {
-		log.Printf("table render failed: %v", err)
-	}
-
-	// Write all content to file
-	WriteToFile(username, fileContent.String())
-}
-
-// BuildDomains generates a list of potential domains using the username and common TLDs.
-func BuildDomains(username string) []string {
-	// List of common top-level domains
-	tlds := []string{
-		".com",
-		".net",
-		".org",
-		".biz",
-		".info",
-		".name",
-		".pro",
-		".cat",
-		".co",
-		".me",
-		".io",
-		".tech",
-		".dev",
-		".app",
-		".shop",
-		".fail",
-		".xyz",
-		".blog",
-		".portfolio",
-		".store",
-		".online",
-		".about",
-		".space",
-		".lol",
-		".fun",
-		".social",
+		fmt.Printf("Error configuring output: %v\n", err)
+		os.Exit(1)
 	}
-
-	// Generate domains by appending TLDs to username
-	var domains []string
-	for _, tld := range tlds {
-		domains = append(domains, username+tld)
+	handlers := []gosearch.Handler{&TerminalHandler{Username: username, Domains: len(gosearch.BuildDomains(username, domains.TLDs))}}
+	for _, sink := range sinks {
+		handlers = append(handlers, sink)
 	}
 
-	return domains
-}
-
-// SearchDomains checks if domains associated with the username exist.
-func SearchDomains(username string, domains []string, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	// Initialize HTTP client
-	client := &http.Client{}
-	Yellow("[*] Searching ", len(domains), " domains with the username ", username, "...").Println()
-
-	// Track number of found domains
-	domainCount := 0
-	// Initialize table for output
-	table := tablewriter.NewWriter(os.Stdout)
-	table.Header("NO", "DOMAIN", "STATUS")
-
-	// Counter for table rows
-	x := 0
-	// Check each domain
-	for _, domain := range domains {
-		url := "http://" + domain
-
-		// Create HTTP request
-		req, err := http.NewRequest(http.MethodGet, url, nil)
-		if err != nil {
-			fmt.Printf("Error creating request for %s: %v\n", domain, err)
-			continue
-		}
-		// Set request headers
-		req.Header.Set("User-Agent", DefaultUserAgent)
-		req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-		req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-		req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-		req.Header.Set("Connection", "keep-alive")
-		req.Header.Set("Upgrade-Insecure-Requests", "1")
-		req.Header.Set("Sec-Fetch-Dest", "document")
-		req.Header.Set("Sec-Fetch-Mode", "navigate")
-		req.Header.Set("Sec-Fetch-Site", "none")
-		req.Header.Set("Sec-Fetch-User", "?1")
-		req.Header.Set("Cache-Control", "max-age=0")
-
-		// Send request
-		resp, err := client.Do(req)
+	// Configure webhook notifications if any webhooks were provided
+	if len(webhooks) > 0 {
+		notifier, err := NewNotifier(webhooks, *notifyMode, network.Webhooks)
 		if err != nil {
-			var netErr net.Error
-			ok := errors.As(err, &netErr)
-			// Check for specific network errors indicating non-existent domains
-			noSuchHostError := strings.Contains(err.Error(), "no such host")
-			networkTimeoutError := ok && netErr.Timeout()
-
-			if !noSuchHostError && !networkTimeoutError {
-				fmt.Printf("Error sending request for %s: %v\n", domain, err)
-			}
-
-			continue
-		}
-		defer resp.Body.Close()
-
-		// Check if domain exists (HTTP 200)
-		if resp.StatusCode == http.StatusOK {
-			x++
-			table.Append(x, domain, Green(http.StatusOK))
-			WriteToFile(username, "[+] 200 OK: "+domain)
-			domainCount++
-		}
-	}
-
-	// Render table
-	if err := table.Render(); err != nil {
-		log.Printf("table render failed: %v", err)
-	}
-	// Display results
-	if domainCount > 0 {
-		Greenf("[+] Found %d domains with the username %s", domainCount, username).Println()
-		WriteToFile(username, "[+] Found "+strconv.Itoa(domainCount)+" domains with the username: "+username)
-	} else {
-		Redf("[-] No domains found with the username %s", username).Println()
-		WriteToFile(username, "[-] No domains found with the username: "+username)
-	}
-}
-
-// SearchProxyNova checks ProxyNova for compromised passwords associated with the username.
-func SearchProxyNova(username string, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	Yellow("[*] Searching ", username, " on ProxyNova for any compromised passwords...").Println()
-
-	// Initialize HTTP client
-	client := &http.Client{}
-
-	// Create request
-	req, err := http.NewRequest(http.MethodGet, "https://api.proxynova.com/comb?query="+username, nil)
-	if err != nil {
-		fmt.Printf("Error creating request: %v\n", err)
-		return
-	}
-
-	// Send request
-	resp, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("Error sending request: %v\n", err)
-		return
-	}
-	defer resp.Body.Close()
-
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Println("Error reading response in SearchProxyNova function:", err)
-		return
-	}
-
-	// Parse JSON response
-	var response ProxyNova
-	err = sonic.Unmarshal(body, &response)
-	if err != nil {
-		fmt.Println("Error parsing JSON in SearchProxyNova function:", err)
-		return
-	}
-
-	// Check if compromised credentials were found
-	if response.Count > 0 {
-		// Initialize table
-		table := tablewriter.NewTable(os.Stdout)
-		table.Header("No", "Email", "Password")
-		Greenf("[+] Found %d compromised passwords for %s:\n", response.Count, username).Println()
-		// Process each credential
-		for i, element := range response.Lines {
-			parts := strings.Split(element, ":")
-			if len(parts) == 2 {
-				email := parts[0]
-				password := parts[1]
-				table.Append(i+1, Green(email), Red(password))
-				WriteToFile(username, "[+] Email: "+email+"\n"+"[+] Password: "+password+"\n\n")
-			}
-		}
-		if err := table.Render(); err != nil {
-			log.Printf("table render failed: %v", err)
-		}
-	} else {
-		Red("[-] No compromised passwords found for ", username, ".").Println()
-	}
-}
-
-// SearchBreachDirectory searches Breach Directory for compromised credentials using an API key.
-func SearchBreachDirectory(username string, apikey string, wg *sync.WaitGroup) {
-	defer wg.Done()
-
-	// Initialize Breach Directory client
-	client, err := gobreach.NewBreachDirectoryClient(apikey)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	Yellow("[*] Searching ", username, " on Breach Directory for any compromised passwords...").Println()
-
-	// Search for breaches
-	response, err := client.Search(username)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	// Check if no breaches were found
-	if response.Found == 0 {
-		Redf("[-] No breaches found for %s.", username).Println()
-		WriteToFile(username, "[-] No breaches found on Breach Directory for: "+username)
-	}
-
-	// Display found breaches
-	Greenf("[+] Found %d breaches for %s:\n", response.Found, username).Println()
-	for _, entry := range response.Result {
-		// Attempt to crack hash
-		pass := CrackHash(entry.Hash)
-		if pass != "" {
-			Green("[+] Password:", pass).Println()
-			WriteToFile(username, "[+] Password: "+pass)
-		} else {
-			Green("[+] Password:", entry.Password).Println()
-			WriteToFile(username, "[+] Password: "+entry.Password)
-		}
-
-		Green("[+] SHA1:", entry.Sha1).Println()
-		Green("[+] Source:", entry.Sources).Println()
-		Green("[+] SHA1:", entry.Sha1)
-		WriteToFile(username, "[+] Source: "+entry.Sources)
-	}
-}
-
-// CrackHash attempts to crack a password hash using the Weakpass API.
-func CrackHash(hash string) string {
-	// Initialize HTTP client
-	client := &http.Client{}
-	// Construct API URL
-	url := fmt.Sprintf("https://weakpass.com/api/v1/search/%s.json", hash)
-
-	// Create request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		fmt.Printf("Error creating request in function CrackHash: %v\n", err)
-		return ""
-	}
-
-	// Set request headers
-	req.Header.Set("User-Agent", DefaultUserAgent)
-	req.Header.Set("accept", "application/json")
-
-	// Send request
-	res, err := client.Do(req)
-	if err != nil {
-		fmt.Printf("Error fetching response in function CrackHash: %v\n", err)
-		return ""
-	}
-	defer res.Body.Close()
-
-	// Read response body
-	jsonData, err := io.ReadAll(res.Body)
-	if err != nil {
-		fmt.Printf("Error reading response JSON: %v\n", err)
-		return ""
-	}
-
-	// Parse JSON response
-	var weakpass WeakpassResponse
-	err = sonic.Unmarshal(jsonData, &weakpass)
-	if err != nil {
-		fmt.Printf("Error unmarshalling JSON: %v\n", err)
-		return ""
-	}
-	// Return cracked password
-	return weakpass.Pass
-}
-
-// MakeRequestWithResponseURL checks for profile existence by comparing the response URL.
-func MakeRequestWithResponseURL(website Website, url string, username string) {
-	// Some websites always return a 200 for existing and non-existing profiles.
-	// If we do not follow redirects, we could get a 301 for existing profiles and 302 for non-existing profiles.
-	// That is why we have the follow_redirects in our website struct.
-	// However, sometimes the website returns 301 for existing profiles and non-existing profiles.
-	// This means even if we do not follow redirects, we still get false positives.
-	// To mitigate this, we can examine the response url to check for non-existing profiles.
-	// Usually, a response url pointing to where the profile should be is returned for existing profiles.
-	// If the response url is not pointing to where the profile should be, then the profile does not exist.
-
-	// Initialize HTTP client with timeout and transport settings
-	client := &http.Client{
-		Timeout: 120 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
-		Jar: nil,
-	}
-
-	// Disable redirects if specified
-	if !website.FollowRedirects {
-		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		}
-	}
-
-	// Set User-Agent
-	userAgent := DefaultUserAgent
-	if website.UserAgent != "" {
-		userAgent = website.UserAgent
-	}
-
-	// Create request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		fmt.Printf("Error creating request in function MakeRequestWithResponseURL: %v\n", err)
-		return
-	}
-
-	// Set request headers
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-	req.Header.Set("Sec-Fetch-Dest", "document")
-	req.Header.Set("Sec-Fetch-Mode", "navigate")
-	req.Header.Set("Sec-Fetch-Site", "none")
-	req.Header.Set("Sec-Fetch-User", "?1")
-	req.Header.Set("Cache-Control", "max-age=0")
-
-	// Add cookies if specified
-	if website.Cookies != nil {
-		for _, cookie := range website.Cookies {
-			cookieObj := &http.Cookie{
-				Name:  cookie.Name,
-				Value: cookie.Value,
-			}
-			req.AddCookie(cookieObj)
-		}
-	}
-
-	// Send request
-	res, err := client.Do(req)
-	if err != nil {
-		return
-	}
-	defer res.Body.Close()
-
-	// Check for error status codes
-	if res.StatusCode >= 400 {
-		return
-	}
-
-	// Compare response URL with expected URL
-	formattedResponseURL := BuildURL(website.ResponseURL, username)
-	if !(res.Request.URL.String() == formattedResponseURL) {
-		url = BuildURL(website.BaseURL, username)
-		Green("[+]", website.Name, ":", url).Println()
-		WriteToFile(username, url+"\n")
-		count.Add(1)
-	}
-}
-
-// MakeRequestWithErrorCode checks for profile existence by comparing HTTP status codes.
-func MakeRequestWithErrorCode(website Website, url string, username string) {
-	// Initialize HTTP client
-	client := &http.Client{
-		Timeout: 120 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
-		Jar: nil,
-	}
-
-	// Disable redirects if specified
-	if !website.FollowRedirects {
-		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
+			fmt.Printf("Error configuring webhooks: %v\n", err)
+			os.Exit(1)
 		}
+		handlers = append(handlers, notifier)
+		sinks = append(sinks, notifier)
 	}
 
-	// Set User-Agent
-	userAgent := DefaultUserAgent
-	if website.UserAgent != "" {
-		userAgent = website.UserAgent
-	}
-
-	// Create request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+	// Load website data from JSON
+	data, catalogNames, err := catalogs.load(network.Transport)
 	if err != nil {
-		fmt.Printf("Error creating request in function MakeRequestWithErrorCode: %v\n", err)
-		return
-	}
-
-	// Set request headers
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-	req.Header.Set("Sec-Fetch-Dest", "document")
-	req.Header.Set("Sec-Fetch-Mode", "navigate")
-	req.Header.Set("Sec-Fetch-Site", "none")
-	req.Header.Set("Sec-Fetch-User", "?1")
-	req.Header.Set("Cache-Control", "max-age=0")
-
-	// Add cookies if specified
-	if website.Cookies != nil {
-		for _, cookie := range website.Cookies {
-			cookieObj := &http.Cookie{
-				Name:  cookie.Name,
-				Value: cookie.Value,
-			}
-			req.AddCookie(cookieObj)
-		}
+		fmt.Printf("Error loading catalogs: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Send request
-	res, err := client.Do(req)
-	if err != nil {
-		return
-	}
-	defer res.Body.Close()
+	// Check the secrets and exclusions against every website, not only those searched
+	secretWarnings := network.secrets.Check(data)
+	filterWarnings := data.FilterWarnings(filters.filter())
 
-	// Check for error status codes
-	if res.StatusCode >= 400 {
-		return
+	// Only search the websites chosen with --sites, --tags and their exclusions
+	filter := filters.filter()
+	if err := data.CheckFilter(filter); err != nil {
+		fmt.Printf("Error filtering websites: %v\n", err)
+		os.Exit(1)
 	}
+	data = data.Filter(filter)
 
-	// Check if status code differs from error code
-	if res.StatusCode != website.ErrorCode {
-		url = BuildURL(website.BaseURL, username)
-		Green("[+] ", website.Name, ":", url).Println()
-		WriteToFile(username, url+"\n")
-		count.Add(1)
+	// Clear the terminal screen
+	screen.Clear()
+	// Display ASCII logo and version
+	fmt.Print(ASCII)
+	fmt.Println(VERSION)
+	// Print separator line
+	fmt.Println(strings.Repeat("⎯", 85))
+	// Display search parameters
+	fmt.Println(":: Username                              : ", username)
+	fmt.Println(":: Websites                              : ", len(data.Websites))
+	if len(catalogNames) > 1 || catalogs.noUpstream {
+		fmt.Println(":: Catalogs                              : ", strings.Join(catalogNames, ", "))
 	}
-}
 
-// MakeRequestWithErrorMsg checks for profile existence by searching for an error message in the response body.
-func MakeRequestWithErrorMsg(website Website, url string, username string) {
-	// Initialize HTTP client
-	client := &http.Client{
-		Timeout: 120 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
-		Jar: nil,
+	// Display website filters if any were set
+	if len(filter.Sites) > 0 {
+		fmt.Println(":: Sites                                 : ", strings.Join(filter.Sites, ", "))
 	}
-
-	// Disable redirects if specified
-	if !website.FollowRedirects {
-		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		}
+	if len(filter.ExcludeSites) > 0 {
+		fmt.Println(":: Excluded Sites                        : ", strings.Join(filter.ExcludeSites, ", "))
 	}
-
-	// Set User-Agent
-	userAgent := DefaultUserAgent
-	if website.UserAgent != "" {
-		userAgent = website.UserAgent
+	if len(filter.Tags) > 0 {
+		fmt.Println(":: Tags                                  : ", strings.Join(filter.Tags, ", "))
 	}
-
-	// Create request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		fmt.Printf("Error creating request in function MakeRequestWithErrorMsg: %v\n", err)
-		return
+	if len(filter.ExcludeTags) > 0 {
+		fmt.Println(":: Excluded Tags                         : ", strings.Join(filter.ExcludeTags, ", "))
 	}
-
-	// Set request headers
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-	req.Header.Set("Sec-Fetch-Dest", "document")
-	req.Header.Set("Sec-Fetch-Mode", "navigate")
-	req.Header.Set("Sec-Fetch-Site", "none")
-	req.Header.Set("Sec-Fetch-User", "?1")
-	req.Header.Set("Cache-Control", "max-age=0")
-
-	// Add cookies if specified
-	if website.Cookies != nil {
-		for _, cookie := range website.Cookies {
-			cookieObj := &http.Cookie{
-				Name:  cookie.Name,
-				Value: cookie.Value,
-			}
-			req.AddCookie(cookieObj)
-		}
+	if filter.IncludeNSFW {
+		fmt.Println(":: NSFW Websites                         : ", filter.IncludeNSFW)
 	}
 
-	// Send request
-	res, err := client.Do(req)
-	if err != nil {
-		return
+	// Display the TLDs if they were changed
+	if domains.Custom {
+		fmt.Println(":: TLDs                                  : ", strings.Join(domains.TLDs, ", "))
 	}
-	// Handle response body compression
-	var reader io.ReadCloser
-	switch res.Header.Get("Content-Encoding") {
-	case "gzip":
-		gzReader, err := gzip.NewReader(res.Body)
-		if err != nil {
-			fmt.Printf("Error creating gzip reader: %v\n", err)
-			return
-		}
-		reader = gzReader
-	case "deflate":
-		zlibReader, err := zlib.NewReader(res.Body)
-		if err != nil {
-			fmt.Printf("Error creating deflate reader: %v\n", err)
-			return
-		}
-		reader = zlibReader
-	case "br":
-		reader = io.NopCloser(brotli.NewReader(res.Body))
-	default:
-		reader = res.Body
-	}
-	defer res.Body.Close()
 
-	// Check for error status codes
-	if res.StatusCode >= 400 {
-		return
+	// Display false positives setting if enabled
+	if *noFalsePositivesFlag {
+		fmt.Println(":: No False Positives                    : ", *noFalsePositivesFlag)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(reader)
-	if err != nil {
-		fmt.Printf("Error reading response body: %v\n", err)
-		return
+	// Display cache setting if enabled
+	if network.cache != nil {
+		fmt.Println(":: Response Cache                        : ", netFlags.cacheDir)
 	}
 
-	// Check for error message
-	bodyStr := string(body)
-	if !strings.Contains(bodyStr, website.ErrorMsg) {
-		url = BuildURL(website.BaseURL, username)
-		Green("[+] ", website.Name, ":", url).Println()
-		WriteToFile(username, url+"\n")
-		count.Add(1)
+	// Display recording or replay setting if enabled
+	if netFlags.record != "" {
+		fmt.Println(":: Recording To                          : ", netFlags.record)
 	}
-}
-
-// MakeRequestWithProfilePresence checks for profile existence by searching for a profile indicator in the response body.
-func MakeRequestWithProfilePresence(website Website, url string, username string) {
-	// Some websites have an indicator that a profile exists
-	// but do not have an indicator when a profile does not exist.
-	// If a profile indicator is not found, we can assume that the profile does not exist.
-
-	// Initialize HTTP client
-	client := &http.Client{
-		Timeout: 120 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
-			DialContext: (&net.Dialer{
-				Timeout:   30 * time.Second,
-				KeepAlive: 30 * time.Second,
-				DualStack: true,
-			}).DialContext,
-			MaxIdleConns:          100,
-			IdleConnTimeout:       90 * time.Second,
-			TLSHandshakeTimeout:   10 * time.Second,
-			ExpectContinueTimeout: 1 * time.Second,
-		},
-		Jar: nil,
+	if netFlags.replay != "" {
+		fmt.Println(":: Replaying From                        : ", netFlags.replay)
 	}
 
-	// Disable redirects if specified
-	if !website.FollowRedirects {
-		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		}
-	}
+	// Print separator line
+	fmt.Println(strings.Repeat("⎯", 85))
+	fmt.Println()
 
-	// Set User-Agent
-	userAgent := DefaultUserAgent
-	if website.UserAgent != "" {
-		userAgent = website.UserAgent
+	// Warn about false positives if not disabled
+	if !*noFalsePositivesFlag {
+		fmt.Println("[!] A yellow link indicates that I was unable to verify whether the username exists on the platform.")
 	}
 
-	// Create request
-	req, err := http.NewRequest(http.MethodGet, url, nil)
-	if err != nil {
-		fmt.Printf("Error creating request in function MakeRequestWithErrorMsg: %v\n", err)
-		return
+	// Warn that sensitive websites are being searched
+	if filter.IncludeNSFW {
+		fmt.Println("[!] Adult and otherwise sensitive websites are included; their results are marked [NSFW].")
 	}
 
-	// Set request headers
-	req.Header.Set("User-Agent", userAgent)
-	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8")
-	req.Header.Set("Accept-Language", "en-US,en;q=0.5")
-	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
-	req.Header.Set("Connection", "keep-alive")
-	req.Header.Set("Upgrade-Insecure-Requests", "1")
-	req.Header.Set("Sec-Fetch-Dest", "document")
-	req.Header.Set("Sec-Fetch-Mode", "navigate")
-	req.Header.Set("Sec-Fetch-Site", "none")
-	req.Header.Set("Sec-Fetch-User", "?1")
-	req.Header.Set("Cache-Control", "max-age=0")
-
-	// Add cookies if specified
-	if website.Cookies != nil {
-		for _, cookie := range website.Cookies {
-			cookieObj := &http.Cookie{
-				Name:  cookie.Name,
-				Value: cookie.Value,
-			}
-			req.AddCookie(cookieObj)
-		}
+	// Warn that catalogs may not be authentic
+	if catalogs.allowUnsigned {
+		fmt.Println("[!] Unsigned remote catalogs are allowed; a tampered catalog could send your searches anywhere.")
 	}
 
-	// Send request
-	res, err := client.Do(req)
-	if err != nil {
-		return
+	// Warn that certificates are not being verified
+	if network.insecure {
+		fmt.Println("[!] TLS certificate verification is disabled; only use --insecure against targets you trust.")
 	}
-	defer res.Body.Close()
 
-	// Check for error status codes
-	if res.StatusCode >= 400 {
-		return
+	// Warn about exclusions that match no website
+	for _, warning := range filterWarnings {
+		fmt.Println("[!] " + warning)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		fmt.Printf("Error reading response body: %v\n", err)
-		return
+	// Warn about secrets that will never be sent
+	for _, warning := range secretWarnings {
+		fmt.Println("[!] " + warning)
 	}
 
-	// Check for profile indicator
-	bodyStr := string(body)
-	if strings.Contains(bodyStr, website.ErrorMsg) {
-		Greenf("[+] %s: %s", website.Name, url).Println()
-		WriteToFile(username, url+"\n")
-		count.Add(1)
+	// Run every search stage, streaming results to every output
+	options := network.Options()
+	options.NoFalsePositives = *noFalsePositivesFlag
+	domains.apply(&options)
+	if *breachDirectoryAPIKey != "" {
+		options.BreachDirectoryAPIKey = *breachDirectoryAPIKey
+	} else {
+		options.BreachDirectoryAPIKey = *breachDirectoryAPIKeyLong
 	}
-}
+	searcher := gosearch.NewSearcher(data, options)
+	searcher.Run(context.Background(), username, gosearch.Handlers(handlers...))
 
-// Search performs concurrent searches across all configured websites.
-func Search(data Data, username string, noFalsePositives bool, wg *sync.WaitGroup) {
-	// Iterate over websites
-	for _, website := range data.Websites {
-		// Run search in a goroutine
-		go func(website Website) {
-			var url string
-			defer wg.Done()
-
-			// Use probe URL if specified, otherwise use base URL
-			if website.URLProbe != "" {
-				url = BuildURL(website.URLProbe, username)
-			} else {
-				url = BuildURL(website.BaseURL, username)
-			}
-
-			// Handle different error types
-			switch website.ErrorType {
-			case "status_code":
-				MakeRequestWithErrorCode(website, url, username)
-			case "errorMsg":
-				MakeRequestWithErrorMsg(website, url, username)
-			case "profilePresence":
-				MakeRequestWithProfilePresence(website, url, username)
-			case "response_url":
-				MakeRequestWithResponseURL(website, url, username)
-			default:
-				// Handle unverified profiles if false positives are allowed
-				if !noFalsePositives {
-					Yellowf("[?] %s: %s", website.Name, url).Println()
-					WriteToFile(username, "[?] "+url+"\n")
-					count.Add(1)
-				}
-			}
-		}(website)
+	// Flush output files and pending webhook posts; errors have already been reported
+	for _, sink := range sinks {
+		sink.Close()
 	}
+	network.Close()
 }
 
-// DeleteOldFile removes any existing output file for the username.
-func DeleteOldFile(username string) {
-	filename := fmt.Sprintf("%s.txt", username)
-	os.Remove(filename)
+// LoadData fetches the latest website configuration using transport, verifying its signature according to trust.
+func LoadData(transport http.RoundTripper, trust gosearch.CatalogTrust) (gosearch.Data, error) {
+	// GoSearch reads the latest data.json straight from the repository, so there is nothing to download or keep up to date.
+	// A data.json in the working directory is left alone: it may be a catalog given with --catalog or one being edited.
+	return gosearch.FetchData(&http.Client{Transport: transport}, trust)
 }
 
 // Text creates a colored string using the specified color code.
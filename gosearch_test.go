@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// failingTransport fails every request, standing in for a network that cannot be reached.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network unreachable")
+}
+
+func TestLoadDataKeepsLocalCatalog(t *testing.T) {
+	t.Chdir(t.TempDir())
+	catalog := []byte(`{"websites": []}`)
+	if err := os.WriteFile("data.json", catalog, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := LoadData(failingTransport{}, gosearch.DefaultCatalogTrust()); err == nil {
+		t.Fatal("LoadData succeeded without a network")
+	}
+	content, err := os.ReadFile("data.json")
+	if err != nil {
+		t.Fatalf("data.json in the working directory was removed: %v", err)
+	}
+	if string(content) != string(catalog) {
+		t.Errorf("data.json was changed to %s", content)
+	}
+}
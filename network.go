@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// networkFlags holds the command-line flags controlling how GoSearch connects to the internet.
+// They are shared by the search command and the serve subcommand.
+type networkFlags struct {
+	proxy         string
+	proxyList     string
+	proxyRotation string
+	proxyCooldown time.Duration
+	retries       int
+	retryBackoff  time.Duration
+	retryBudget   int
+	headerProfile string
+	http2         bool
+	insecure      bool
+	secrets       string
+	cache         bool
+	cacheDir      string
+	cacheTTL      time.Duration
+	record        string
+	replay        string
+}
+
+// register defines the network flags on flags.
+func (f *networkFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.proxy, "proxy", "", "Send every request through a proxy (http, https, socks5 or socks5h URL)")
+	flags.StringVar(&f.proxyList, "proxy-list", "", "File of proxy URLs, one per line, to rotate requests across")
+	flags.StringVar(&f.proxyRotation, "proxy-rotation", gosearch.RotateRoundRobin, "Proxy list rotation: round-robin or random")
+	flags.DurationVar(&f.proxyCooldown, "proxy-cooldown", gosearch.DefaultProxyCooldown, "How long to stop using a proxy after it fails or is blocked")
+	flags.IntVar(&f.retries, "retries", 2, "Retries per request after network errors, 429 or 5xx responses (0 disables retries)")
+	flags.DurationVar(&f.retryBackoff, "retry-backoff", gosearch.DefaultRetryBackoff, "Base delay before retrying a request; doubles with each retry")
+	flags.IntVar(&f.retryBudget, "retry-budget", gosearch.DefaultRetryBudget, "Maximum retries across the whole search")
+	flags.StringVar(&f.headerProfile, "header-profile", gosearch.DefaultHeaderProfile, "Browser headers to send: "+strings.Join(gosearch.HeaderProfileNames(), ", "))
+	flags.BoolVar(&f.http2, "http2", false, "Negotiate HTTP/2 with websites that support it")
+	flags.StringVar(&f.secrets, "secrets", os.Getenv("GOSEARCH_SECRETS"), "JSON file of per-website cookies, bearer tokens and API key headers (defaults to $GOSEARCH_SECRETS)")
+	flags.BoolVar(&f.cache, "cache", false, "Cache responses on disk and reuse them in later runs")
+	flags.StringVar(&f.cacheDir, "cache-dir", defaultCacheDir(), "Directory to cache responses in")
+	flags.DurationVar(&f.cacheTTL, "cache-ttl", gosearch.DefaultCacheTTL, "How long cached responses are reused")
+	flags.StringVar(&f.record, "record", "", "Directory to record every response to, for replaying the run later")
+	flags.StringVar(&f.replay, "replay", "", "Directory of recorded responses to serve instead of using the network")
+	flags.BoolVar(&f.insecure, "insecure", false, "Skip TLS certificate verification when searching (for lab targets with self-signed certificates)")
+}
+
+// Network holds the parsed network settings.
+type Network struct {
+	Proxy     *url.URL            // Single proxy for every request, if set
+	ProxyPool *gosearch.ProxyPool // Rotating proxies for every request, if set
+	Transport http.RoundTripper   // Transport for GoSearch's own requests for catalogs, recorded and replayed with the search
+	Webhooks  http.RoundTripper   // Transport for webhooks, never recorded or replayed so their URLs stay out of recordings
+
+	retries       int
+	retryBackoff  time.Duration
+	retryBudget   int
+	headerProfile string
+	http2         bool
+	insecure      bool
+	secrets       gosearch.Secrets
+	cache         *gosearch.Cache
+	record        *gosearch.Recorder
+	replay        *gosearch.Replayer
+}
+
+// parse validates the network flags.
+func (f *networkFlags) parse() (Network, error) {
+	network := Network{
+		retries:       f.retries,
+		retryBackoff:  f.retryBackoff,
+		retryBudget:   f.retryBudget,
+		headerProfile: f.headerProfile,
+		http2:         f.http2,
+		insecure:      f.insecure,
+	}
+
+	if _, err := gosearch.LookupHeaderProfile(f.headerProfile); err != nil {
+		return Network{}, err
+	}
+
+	if f.proxy != "" {
+		proxy, err := gosearch.ParseProxy(f.proxy)
+		if err != nil {
+			return Network{}, err
+		}
+		network.Proxy = proxy
+	}
+
+	if f.secrets != "" {
+		file, err := os.Open(f.secrets)
+		if err != nil {
+			return Network{}, fmt.Errorf("error opening secrets file: %w", err)
+		}
+		defer file.Close()
+
+		network.secrets, err = gosearch.ReadSecrets(file)
+		if err != nil {
+			return Network{}, fmt.Errorf("%s: %w", f.secrets, err)
+		}
+	}
+
+	if f.cache {
+		cache, err := gosearch.NewCache(f.cacheDir, f.cacheTTL)
+		if err != nil {
+			return Network{}, err
+		}
+		network.cache = cache
+	}
+
+	if f.record != "" && f.replay != "" {
+		return Network{}, errors.New("--record and --replay cannot be used together")
+	}
+	if f.replay != "" {
+		replay, err := gosearch.NewReplayer(f.replay)
+		if err != nil {
+			return Network{}, err
+		}
+		network.replay = replay
+	}
+
+	if f.proxyList != "" {
+		file, err := os.Open(f.proxyList)
+		if err != nil {
+			return Network{}, fmt.Errorf("error opening proxy list: %w", err)
+		}
+		defer file.Close()
+
+		proxies, err := gosearch.ReadProxyList(file)
+		if err != nil {
+			return Network{}, err
+		}
+		network.ProxyPool, err = gosearch.NewProxyPool(proxies, f.proxyRotation, f.proxyCooldown)
+		if err != nil {
+			return Network{}, err
+		}
+	}
+
+	network.Transport = gosearch.NewTransport(network.Proxy)
+	if network.ProxyPool != nil {
+		network.Transport = network.ProxyPool.Transport(gosearch.NewTransport(nil))
+	}
+	network.Webhooks = network.Transport
+
+	// Record or replay GoSearch's own requests too, so a replayed run loads the recorded data.json
+	if network.replay != nil {
+		network.Transport = network.replay.Transport()
+	}
+	if f.record != "" {
+		record, err := gosearch.NewRecorder(f.record)
+		if err != nil {
+			return Network{}, err
+		}
+		network.record = record
+		network.Transport = record.Transport(network.Transport)
+	}
+	return network, nil
+}
+
+// Close finishes the recording, if recording.
+func (n Network) Close() error {
+	if n.record == nil {
+		return nil
+	}
+	return n.record.Close()
+}
+
+// Options returns search options using the network settings.
+func (n Network) Options() gosearch.Options {
+	return gosearch.Options{
+		Proxy:         n.Proxy,
+		ProxyPool:     n.ProxyPool,
+		Retries:       n.retries,
+		RetryBackoff:  n.retryBackoff,
+		RetryBudget:   n.retryBudget,
+		HeaderProfile: n.headerProfile,
+		HTTP2:         n.http2,
+		Insecure:      n.insecure,
+		Secrets:       n.secrets,
+		Cache:         n.cache,
+		Record:        n.record,
+		Replay:        n.replay,
+	}
+}
+
+// defaultCacheDir returns the default response cache directory under the user's cache directory.
+func defaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "gosearch")
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// Supported webhook payload formats.
+const (
+	WebhookJSON       = "json"       // Generic JSON payload with the raw finding or summary
+	WebhookSlack      = "slack"      // Slack incoming webhook payload
+	WebhookDiscord    = "discord"    // Discord webhook payload
+	WebhookMattermost = "mattermost" // Mattermost incoming webhook payload
+)
+
+// Notification modes selecting which events are posted to webhooks.
+const (
+	NotifySummary  = "summary"  // Post only the run summary
+	NotifyFindings = "findings" // Post only individual findings
+	NotifyAll      = "all"      // Post both findings and the run summary
+)
+
+// Webhook represents a single webhook destination.
+type Webhook struct {
+	URL    string // Webhook URL
+	Format string // Payload format (json, slack, discord, mattermost)
+}
+
+// Summary represents the outcome of a complete search run.
+type Summary struct {
+	Username      string            `json:"username"`       // Searched username
+	Websites      int               `json:"websites"`       // Number of websites searched
+	ProfilesFound int               `json:"profiles_found"` // Number of profiles found
+	Elapsed       string            `json:"elapsed"`        // Total time taken
+	Profiles      []gosearch.Result `json:"profiles"`       // Profiles found during the run
+	Domains       []string          `json:"domains"`        // Domains found during the run
+}
+
+// Notifier posts run summaries and individual findings as JSON to webhook URLs.
+// It implements gosearch.Handler so it can consume a search's result stream. Events are queued and posted in
+// order by a background goroutine, so a slow webhook never holds up the search; Close waits for the queue.
+type Notifier struct {
+	Webhooks []Webhook // Destinations to post to
+	Mode     string    // Which events to post (summary, findings, all)
+
+	client *http.Client
+	mu     sync.Mutex
+	queue  []notification // Events waiting to be posted
+	closed bool           // Whether Close has been called
+	wake   chan struct{}  // Signals the posting goroutine that the queue or closed changed
+	done   chan struct{}  // Closed once the posting goroutine has finished
+}
+
+// notification is an event waiting to be posted to the webhooks.
+type notification struct {
+	event map[string]interface{} // Raw event for JSON webhooks
+	text  string                 // Human-readable text for chat webhooks
+}
+
+// NewNotifier creates a Notifier for the given webhooks and notification mode, posting through transport.
+func NewNotifier(webhooks []Webhook, mode string, transport http.RoundTripper) (*Notifier, error) {
+	switch mode {
+	case NotifySummary, NotifyFindings, NotifyAll:
+	default:
+		return nil, fmt.Errorf("unknown notification mode %q (expected summary, findings or all)", mode)
+	}
+
+	n := &Notifier{
+		Webhooks: webhooks,
+		Mode:     mode,
+		client:   &http.Client{Timeout: 15 * time.Second, Transport: transport},
+		wake:     make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	go n.run()
+	return n, nil
+}
+
+// ParseWebhook parses a webhook flag value of the form [format=]url.
+func ParseWebhook(value string) (Webhook, error) {
+	webhook := Webhook{URL: value, Format: WebhookJSON}
+
+	// An optional format prefix may precede the URL, e.g. slack=https://hooks.slack.com/...
+	if prefix, rest, ok := strings.Cut(value, "="); ok {
+		switch prefix {
+		case WebhookJSON, WebhookSlack, WebhookDiscord, WebhookMattermost:
+			webhook.Format = prefix
+			webhook.URL = rest
+		}
+	}
+
+	if !strings.HasPrefix(webhook.URL, "http://") && !strings.HasPrefix(webhook.URL, "https://") {
+		return Webhook{}, fmt.Errorf("invalid webhook URL %q", webhook.URL)
+	}
+
+	return webhook, nil
+}
+
+// RedactURL returns only the scheme and host of a webhook URL. Slack, Discord and Mattermost webhook URLs carry
+// their secret token in the path, so full URLs must never be printed.
+func RedactURL(webhookURL string) string {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Host == "" {
+		return "webhook"
+	}
+	return u.Scheme + "://" + u.Host + "/..."
+}
+
+// webhookFlag collects repeated --webhook flags.
+type webhookFlag []Webhook
+
+// String returns the configured webhook URLs, redacted.
+func (w *webhookFlag) String() string {
+	urls := make([]string, len(*w))
+	for i, webhook := range *w {
+		urls[i] = RedactURL(webhook.URL)
+	}
+	return strings.Join(urls, ",")
+}
+
+// Set parses and appends a webhook flag value.
+func (w *webhookFlag) Set(value string) error {
+	webhook, err := ParseWebhook(value)
+	if err != nil {
+		return err
+	}
+	*w = append(*w, webhook)
+	return nil
+}
+
+// StageStarted does nothing; stages are not posted.
+func (n *Notifier) StageStarted(stage string) {}
+
+// SiteResult posts found and unverified profiles.
+func (n *Notifier) SiteResult(result gosearch.Result) {
+	if result.Profile() {
+		n.NotifyFinding(result)
+	}
+}
+
+// StageFinished does nothing; stages are not posted.
+func (n *Notifier) StageFinished(stage string, report *gosearch.Report, err error) {}
+
+// RunFinished posts the run summary.
+func (n *Notifier) RunFinished(report *gosearch.Report) {
+	n.NotifySummary(*report)
+}
+
+// Close waits for every queued event to be posted and stops the posting goroutine.
+func (n *Notifier) Close() error {
+	n.mu.Lock()
+	n.closed = true
+	n.mu.Unlock()
+	n.signal()
+	<-n.done
+	return nil
+}
+
+// NotifyFinding posts a found profile to the webhooks if findings are enabled.
+func (n *Notifier) NotifyFinding(result gosearch.Result) {
+	if n.Mode == NotifySummary {
+		return
+	}
+
+	text := fmt.Sprintf("%s %s: %s (username: %s)%s", resultMarker(result), result.Website, result.URL, result.Username, resultLabel(result))
+	n.enqueue(map[string]interface{}{"event": "finding", "finding": result}, text)
+}
+
+// NotifySummary posts the run summary, including all found profiles, if summaries are enabled.
+func (n *Notifier) NotifySummary(report gosearch.Report) {
+	if n.Mode == NotifyFindings {
+		return
+	}
+
+	summary := Summary{
+		Username:      report.Username,
+		Websites:      report.Websites,
+		ProfilesFound: len(report.Profiles),
+		Elapsed:       report.Elapsed,
+		Profiles:      report.Profiles,
+		Domains:       report.Domains,
+	}
+
+	// Build a human-readable summary for chat payloads
+	var text strings.Builder
+	text.WriteString(fmt.Sprintf("GoSearch found %d profiles for %s across %d websites in %s", summary.ProfilesFound, summary.Username, summary.Websites, summary.Elapsed))
+	for _, result := range summary.Profiles {
+		text.WriteString(fmt.Sprintf("\n%s %s: %s%s", resultMarker(result), result.Website, result.URL, resultLabel(result)))
+	}
+	for _, domain := range summary.Domains {
+		text.WriteString(fmt.Sprintf("\n[+] Domain: %s", domain))
+	}
+
+	n.enqueue(map[string]interface{}{"event": "summary", "summary": summary}, text.String())
+}
+
+// resultMarker returns the terminal-style marker for a result: [+] for found, [?] for unverified.
+func resultMarker(result gosearch.Result) string {
+	if result.Status == gosearch.StatusUnverified {
+		return "[?]"
+	}
+	return "[+]"
+}
+
+// enqueue queues an event for the posting goroutine. Events queued after Close are dropped.
+func (n *Notifier) enqueue(event map[string]interface{}, text string) {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	n.queue = append(n.queue, notification{event: event, text: text})
+	n.mu.Unlock()
+	n.signal()
+}
+
+// signal wakes the posting goroutine without blocking.
+func (n *Notifier) signal() {
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run posts queued events in order until the Notifier is closed and its queue is empty.
+func (n *Notifier) run() {
+	defer close(n.done)
+	for {
+		n.mu.Lock()
+		if len(n.queue) == 0 {
+			closed := n.closed
+			n.mu.Unlock()
+			if closed {
+				return
+			}
+			<-n.wake
+			continue
+		}
+		next := n.queue[0]
+		n.queue = n.queue[1:]
+		n.mu.Unlock()
+
+		n.post(next.event, next.text)
+	}
+}
+
+// post sends an event to every webhook, rendering the payload for each webhook's format.
+func (n *Notifier) post(event map[string]interface{}, text string) {
+	for _, webhook := range n.Webhooks {
+		payload, err := sonic.Marshal(WebhookPayload(webhook.Format, event, text))
+		if err != nil {
+			Red("Error encoding webhook payload:").Print()
+			White(" " + err.Error()).Println()
+			continue
+		}
+
+		resp, err := n.client.Post(webhook.URL, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			// Request errors quote the URL; keep its token out of the terminal
+			var urlErr *url.Error
+			if errors.As(err, &urlErr) {
+				urlErr.URL = RedactURL(webhook.URL)
+			}
+			Red("Error sending webhook notification:").Print()
+			White(" " + err.Error()).Println()
+			continue
+		}
+		resp.Body.Close()
+
+		// Check HTTP status
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			Red("Error sending webhook notification:").Print()
+			Whitef(" %s returned status code %d", RedactURL(webhook.URL), resp.StatusCode).Println()
+		}
+	}
+}
+
+// WebhookPayload builds the request body for the given webhook format.
+func WebhookPayload(format string, event map[string]interface{}, text string) interface{} {
+	switch format {
+	case WebhookSlack, WebhookMattermost:
+		return map[string]string{"text": text}
+	case WebhookDiscord:
+		// Discord rejects messages longer than 2000 characters
+		if runes := []rune(text); len(runes) > 2000 {
+			text = string(runes[:1997]) + "..."
+		}
+		return map[string]string{"content": text}
+	default:
+		return event
+	}
+}
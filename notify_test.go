@@ -0,0 +1,235 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// webhookServer is a stand-in for a webhook endpoint, recording every payload posted to it.
+type webhookServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	payloads []map[string]interface{}
+}
+
+// newWebhookServer starts a webhookServer. Requests wait for release to be closed, if it is not nil.
+func newWebhookServer(t *testing.T, release chan struct{}) *webhookServer {
+	t.Helper()
+	s := &webhookServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if release != nil {
+			<-release
+		}
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("payload is not JSON: %s", body)
+		}
+		s.mu.Lock()
+		s.payloads = append(s.payloads, payload)
+		s.mu.Unlock()
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// testFinding and testReport are the events posted in the tests.
+var (
+	testFinding = gosearch.Result{Username: "alice", Website: "GitHub", URL: "https://github.com/alice", Status: gosearch.StatusFound}
+	testReport  = &gosearch.Report{Username: "alice", Websites: 3, Profiles: []gosearch.Result{testFinding}, Domains: []string{"alice.com"}, Elapsed: "1s"}
+)
+
+// notify runs a search's worth of events through a Notifier posting to the server and returns the payloads.
+func notify(t *testing.T, server *webhookServer, format, mode string) []map[string]interface{} {
+	t.Helper()
+	notifier, err := NewNotifier([]Webhook{{URL: server.URL + "/hook", Format: format}}, mode, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.SiteResult(testFinding)
+	notifier.SiteResult(gosearch.Result{Username: "alice", Website: "GitLab", Status: gosearch.StatusNotFound})
+	notifier.RunFinished(testReport)
+	notifier.Close()
+	return server.payloads
+}
+
+func TestWebhookPayloads(t *testing.T) {
+	tests := []struct {
+		format string
+		check  func(t *testing.T, finding, summary map[string]interface{})
+	}{
+		{WebhookJSON, func(t *testing.T, finding, summary map[string]interface{}) {
+			if finding["event"] != "finding" || finding["finding"].(map[string]interface{})["url"] != testFinding.URL {
+				t.Errorf("got finding %v", finding)
+			}
+			got := summary["summary"].(map[string]interface{})
+			if summary["event"] != "summary" || got["profiles_found"] != 1.0 || len(got["domains"].([]interface{})) != 1 {
+				t.Errorf("got summary %v", summary)
+			}
+		}},
+		{WebhookSlack, func(t *testing.T, finding, summary map[string]interface{}) {
+			if text, _ := finding["text"].(string); !strings.Contains(text, "[+] GitHub: "+testFinding.URL) {
+				t.Errorf("got finding %v", finding)
+			}
+			if text, _ := summary["text"].(string); !strings.Contains(text, "found 1 profiles for alice") || !strings.Contains(text, "Domain: alice.com") {
+				t.Errorf("got summary %v", summary)
+			}
+		}},
+		{WebhookDiscord, func(t *testing.T, finding, summary map[string]interface{}) {
+			if content, _ := finding["content"].(string); !strings.Contains(content, "[+] GitHub: "+testFinding.URL) {
+				t.Errorf("got finding %v", finding)
+			}
+			if content, _ := summary["content"].(string); !strings.Contains(content, "found 1 profiles for alice") {
+				t.Errorf("got summary %v", summary)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			payloads := notify(t, newWebhookServer(t, nil), tt.format, NotifyAll)
+			if len(payloads) != 2 {
+				t.Fatalf("got %d payloads, want the finding and the summary: %v", len(payloads), payloads)
+			}
+			tt.check(t, payloads[0], payloads[1])
+		})
+	}
+}
+
+func TestDiscordPayloadLength(t *testing.T) {
+	payload := WebhookPayload(WebhookDiscord, nil, strings.Repeat("x", 3000)).(map[string]string)
+	if n := len([]rune(payload["content"])); n != 2000 {
+		t.Errorf("got %d characters, want 2000", n)
+	}
+}
+
+func TestNotifyModes(t *testing.T) {
+	tests := []struct {
+		mode   string
+		events []string
+	}{
+		{NotifySummary, []string{"summary"}},
+		{NotifyFindings, []string{"finding"}},
+		{NotifyAll, []string{"finding", "summary"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			var events []string
+			for _, payload := range notify(t, newWebhookServer(t, nil), WebhookJSON, tt.mode) {
+				events = append(events, payload["event"].(string))
+			}
+			if strings.Join(events, ",") != strings.Join(tt.events, ",") {
+				t.Errorf("got events %v, want %v", events, tt.events)
+			}
+		})
+	}
+}
+
+func TestNotifierDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	server := newWebhookServer(t, release)
+	notifier, err := NewNotifier([]Webhook{{URL: server.URL, Format: WebhookJSON}}, NotifyAll, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Findings are queued while the webhook hangs
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		notifier.SiteResult(testFinding)
+	}
+	notifier.RunFinished(testReport)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("handling results took %s while the webhook was slow", elapsed)
+	}
+
+	// Close waits for every queued event
+	close(release)
+	notifier.Close()
+	if len(server.payloads) != 6 || server.payloads[5]["event"] != "summary" {
+		t.Errorf("got %d payloads, want 5 findings then the summary", len(server.payloads))
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := map[string]string{
+		"https://hooks.slack.com/services/T000/B000/secret": "https://hooks.slack.com/...",
+		"https://discord.com/api/webhooks/1/token":          "https://discord.com/...",
+		"not a url": "webhook",
+	}
+	for webhookURL, want := range tests {
+		if got := RedactURL(webhookURL); got != want {
+			t.Errorf("RedactURL(%q) = %q, want %q", webhookURL, got, want)
+		}
+	}
+}
+
+func TestWebhookNotRecorded(t *testing.T) {
+	dir := t.TempDir()
+	var netFlags networkFlags
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	netFlags.register(flags)
+	if err := flags.Parse([]string{"--record", dir}); err != nil {
+		t.Fatal(err)
+	}
+	network, err := netFlags.parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Record a catalog request alongside the webhook so the recording is not trivially empty
+	catalog := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"websites": []}`))
+	}))
+	t.Cleanup(catalog.Close)
+	resp, err := (&http.Client{Transport: network.Transport}).Get(catalog.URL + "/data.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	server := newWebhookServer(t, nil)
+	hook := server.URL + "/hook/webhook-token"
+	notifier, err := NewNotifier([]Webhook{{URL: hook, Format: WebhookJSON}}, NotifyAll, network.Webhooks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	notifier.SiteResult(testFinding)
+	notifier.RunFinished(testReport)
+	notifier.Close()
+	if err := network.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(server.payloads) != 2 {
+		t.Fatalf("got %d payloads, want 2", len(server.payloads))
+	}
+
+	var recorded strings.Builder
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded.Write(content)
+	}
+	if !strings.Contains(recorded.String(), "/data.json") {
+		t.Fatalf("the catalog request was not recorded: %s", recorded.String())
+	}
+	if strings.Contains(recorded.String(), "webhook-token") || strings.Contains(recorded.String(), "/hook") {
+		t.Errorf("the recording contains the webhook: %s", recorded.String())
+	}
+}
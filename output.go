@@ -0,0 +1,244 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/olekukonko/tablewriter/renderer"
+	"github.com/olekukonko/tablewriter/tw"
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// TerminalHandler prints search progress and results to the terminal.
+type TerminalHandler struct {
+	Username string // Username being searched
+	Domains  int    // Number of domains checked
+}
+
+// StageStarted announces the start of a search stage.
+func (h *TerminalHandler) StageStarted(stage string) {
+	switch stage {
+	case gosearch.StageHudsonRock:
+		fmt.Println()
+		fmt.Println()
+		Yellow("[*] Searching HudsonRock's Cybercrime Intelligence Database...").Println()
+	case gosearch.StageBreachDirectory:
+		fmt.Println()
+		fmt.Println()
+		Yellow("[*] Searching ", h.Username, " on Breach Directory for any compromised passwords...").Println()
+	case gosearch.StageProxyNova:
+		fmt.Println()
+		fmt.Println()
+		Yellow("[*] Searching ", h.Username, " on ProxyNova for any compromised passwords...").Println()
+	case gosearch.StageDomains:
+		fmt.Println()
+		fmt.Println()
+		Yellow("[*] Searching ", h.Domains, " domains with the username ", h.Username, "...").Println()
+	}
+}
+
+// SiteResult prints a found or unverified profile.
+func (h *TerminalHandler) SiteResult(result gosearch.Result) {
+	switch result.Status {
+	case gosearch.StatusFound:
+		Green("[+] ", result.Website, ":", result.URL, resultLabel(result)).Println()
+	case gosearch.StatusUnverified:
+		Yellowf("[?] %s: %s%s", result.Website, result.URL, resultLabel(result)).Println()
+	}
+}
+
+// StageFinished prints the outcome of a completed search stage.
+func (h *TerminalHandler) StageFinished(stage string, report *gosearch.Report, err error) {
+	switch stage {
+	case gosearch.StageHudsonRock:
+		if err != nil {
+			Red("Error searching HudsonRock:").Print()
+			White(" " + err.Error()).Println()
+			return
+		}
+		PrintHudsonRock(*report.HudsonRock)
+	case gosearch.StageBreachDirectory:
+		if err != nil {
+			Red("Error searching Breach Directory:").Print()
+			White(" " + err.Error()).Println()
+			return
+		}
+		PrintBreachDirectory(h.Username, report.BreachDirectory)
+	case gosearch.StageProxyNova:
+		if err != nil {
+			Red("Error searching ProxyNova:").Print()
+			White(" " + err.Error()).Println()
+			return
+		}
+		PrintProxyNova(h.Username, *report.ProxyNova)
+	case gosearch.StageDomains:
+		// Domain errors are not fatal; report them before the found domains
+		if err != nil {
+			fmt.Println(err)
+		}
+		PrintDomains(h.Username, report.DomainResults)
+	}
+}
+
+// RunFinished prints the number of profiles found and the time taken.
+func (h *TerminalHandler) RunFinished(report *gosearch.Report) {
+	fmt.Println()
+	fmt.Println()
+
+	table := tablewriter.NewTable(os.Stdout, tablewriter.WithRenderer(renderer.NewBlueprint(tw.Rendition{Borders: tw.BorderNone})))
+	table.Append(Bold("Number of profiles found"), Red(len(report.Profiles)))
+	table.Append(Bold("Total time taken"), Green(report.Elapsed))
+	if report.Retries > 0 {
+		table.Append(Bold("Requests retried"), Yellow(report.Retries))
+	}
+	if err := table.Render(); err != nil {
+		log.Printf("table render failed: %v", err)
+	}
+
+	if len(report.Proxies) > 0 {
+		fmt.Println()
+		PrintProxyStats(report.Proxies)
+	}
+}
+
+// PrintProxyStats displays how each proxy in the pool was used.
+func PrintProxyStats(stats []gosearch.ProxyStats) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("Proxy", "Requests", "Failures", "Blocked", "Status")
+
+	for _, proxy := range stats {
+		status := Green("healthy")
+		if !proxy.Healthy {
+			status = Red("cooling down")
+		}
+		table.Append(proxy.Proxy, proxy.Requests, proxy.Failures, proxy.Blocked, status)
+	}
+
+	if err := table.Render(); err != nil {
+		log.Printf("table render failed: %v", err)
+	}
+}
+
+// PrintHudsonRock displays HudsonRock's findings.
+func PrintHudsonRock(response gosearch.HudsonRockResponse) {
+	// Check if no compromises were found
+	if !response.Compromised() {
+		Green("✓ No info-stealer association found").Println()
+		return
+	}
+
+	// Display warning for detected compromises
+	Red("‼ Info-stealer compromise detected").Println()
+	Yellow("  All credentials on this computer may be exposed").Println()
+
+	// Initialize table for terminal output
+	table := tablewriter.NewTable(os.Stdout, tablewriter.WithHeaderConfig(tw.CellConfig{
+		Formatting: tw.CellFormatting{
+			AutoFormat: tw.Off,
+		},
+	}))
+	table.Header([]any{
+		Blue("#"),
+		Blue("Stealer"),
+		Blue("Date"),
+		Blue("Computer"),
+		Blue("Passwords"),
+	})
+
+	// Process each stealer entry
+	for i, stealer := range response.Stealers {
+		// Highlight computer name if valid
+		computerName := stealer.ComputerName
+		if !strings.EqualFold(strings.TrimSpace(computerName), "Not Found") {
+			computerName = Red(computerName).String()
+		}
+		// Add to terminal table
+		table.Append([]string{
+			fmt.Sprintf("%d", i+1),
+			stealer.StealerFamily,
+			formatStealerDate(stealer.DateCompromised),
+			computerName,
+			strings.Join(stealer.TopPasswords, "\n"),
+		})
+	}
+
+	// Render table to terminal
+	if err := table.Render(); err != nil {
+		log.Printf("table render failed: %v", err)
+	}
+}
+
+// PrintDomains displays the registered domains and counts those serving a website.
+func PrintDomains(username string, domains []gosearch.DomainResult) {
+	// Initialize table for output
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("NO", "DOMAIN", "STATUS", "URL", "REGISTRAR", "PARKED")
+
+	registered, found := 0, 0
+	for _, domain := range domains {
+		if domain.Status == gosearch.DomainUnregistered || domain.Status == gosearch.DomainUnknown {
+			continue
+		}
+		status := Yellow(domain.Status)
+		if domain.Found() {
+			status = Green(domain.Status)
+			found++
+		}
+		registered++
+		table.Append(registered, domain.Domain, status, domain.FinalURL, domain.Registrar, domain.Parked)
+	}
+
+	// Render table
+	if err := table.Render(); err != nil {
+		log.Printf("table render failed: %v", err)
+	}
+	// Display results
+	if found > 0 {
+		Greenf("[+] Found %d domains with the username %s", found, username).Println()
+	} else {
+		Redf("[-] No domains found with the username %s", username).Println()
+	}
+}
+
+// PrintProxyNova displays compromised credentials found on ProxyNova.
+func PrintProxyNova(username string, response gosearch.ProxyNova) {
+	// Check if compromised credentials were found
+	if response.Count > 0 {
+		// Initialize table
+		table := tablewriter.NewTable(os.Stdout)
+		table.Header("No", "Email", "Password")
+		Greenf("[+] Found %d compromised passwords for %s:\n", response.Count, username).Println()
+		// Process each credential
+		for i, element := range response.Lines {
+			parts := strings.Split(element, ":")
+			if len(parts) == 2 {
+				table.Append(i+1, Green(parts[0]), Red(parts[1]))
+			}
+		}
+		if err := table.Render(); err != nil {
+			log.Printf("table render failed: %v", err)
+		}
+	} else {
+		Red("[-] No compromised passwords found for ", username, ".").Println()
+	}
+}
+
+// PrintBreachDirectory displays breaches found on Breach Directory.
+func PrintBreachDirectory(username string, breaches []gosearch.Breach) {
+	// Check if no breaches were found
+	if len(breaches) == 0 {
+		Redf("[-] No breaches found for %s.", username).Println()
+		return
+	}
+
+	// Display found breaches
+	Greenf("[+] Found %d breaches for %s:\n", len(breaches), username).Println()
+	for _, breach := range breaches {
+		Green("[+] Password:", breach.Password).Println()
+		Green("[+] SHA1:", breach.Sha1).Println()
+		Green("[+] Source:", breach.Sources).Println()
+	}
+}
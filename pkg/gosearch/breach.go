@@ -0,0 +1,198 @@
+package gosearch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/bytedance/sonic"
+)
+
+// Endpoints holds the base URLs of the breach database and domain lookup APIs GoSearch queries.
+type Endpoints struct {
+	HudsonRock      string // HudsonRock's search-by-username API
+	ProxyNova       string // ProxyNova's COMB API
+	BreachDirectory string // Breach Directory's RapidAPI endpoint
+	Weakpass        string // Weakpass's hash search API
+	DNS             string // DNS-over-HTTPS resolver serving the JSON API
+	RDAP            string // RDAP service answering /domain/<name> queries
+}
+
+// DefaultEndpoints are the public breach database and domain lookup APIs.
+var DefaultEndpoints = Endpoints{
+	HudsonRock:      "https://cavalier.hudsonrock.com/api/json/v2/osint-tools/search-by-username",
+	ProxyNova:       "https://api.proxynova.com/comb",
+	BreachDirectory: "https://breachdirectory.p.rapidapi.com/",
+	Weakpass:        "https://weakpass.com/api/v1/search",
+	DNS:             "https://dns.google/resolve",
+	RDAP:            "https://rdap.org/domain",
+}
+
+// hudsonRockNotFound is the message HudsonRock returns for usernames without info-stealer associations.
+const hudsonRockNotFound = "This username is not associated with a computer infected by an info-stealer. Visit https://www.hudsonrock.com/free-tools to discover additional free tools and Infostealers related data."
+
+// Stealer represents data from an info-stealer compromise.
+type Stealer struct {
+	TotalCorporateServices int         `json:"total_corporate_services"` // Number of corporate services compromised
+	TotalUserServices      int         `json:"total_user_services"`      // Number of user services compromised
+	DateCompromised        string      `json:"date_compromised"`         // Date of compromise
+	StealerFamily          string      `json:"stealer_family"`           // Type of stealer malware
+	ComputerName           string      `json:"computer_name"`            // Name of compromised computer
+	OperatingSystem        string      `json:"operating_system"`         // Operating system of compromised computer
+	MalwarePath            string      `json:"malware_path"`             // Path of malware on compromised system
+	Antiviruses            interface{} `json:"antiviruses"`              // Antivirus software detected
+	IP                     string      `json:"ip"`                       // IP address of compromised system
+	TopPasswords           []string    `json:"top_passwords"`            // Commonly used passwords
+	TopLogins              []string    `json:"top_logins"`               // Commonly used logins
+}
+
+// HudsonRockResponse represents the response from HudsonRock's API.
+type HudsonRockResponse struct {
+	Message  string    `json:"message"`  // Response message
+	Stealers []Stealer `json:"stealers"` // List of stealer data
+}
+
+// Compromised reports whether HudsonRock associated the username with an info-stealer infection.
+func (r HudsonRockResponse) Compromised() bool {
+	return r.Message != hudsonRockNotFound
+}
+
+// WeakpassResponse represents the response from Weakpass API for hash cracking.
+type WeakpassResponse struct {
+	Type string `json:"type"` // Hash type
+	Hash string `json:"hash"` // Hash value
+	Pass string `json:"pass"` // Cracked password
+}
+
+// ProxyNova represents the response from ProxyNova API for compromised passwords.
+type ProxyNova struct {
+	Count int      `json:"count"` // Number of compromised credentials
+	Lines []string `json:"lines"` // List of credential pairs
+}
+
+// breachDirectoryResponse represents the response from Breach Directory's API.
+type breachDirectoryResponse struct {
+	Found  int `json:"found"` // Number of breached credentials
+	Result []struct {
+		Email    string `json:"email"`    // Breached email or username
+		Password string `json:"password"` // Partially masked password
+		Sha1     string `json:"sha1"`     // SHA1 hash of the password
+		Hash     string `json:"hash"`     // Password hash to crack
+		Sources  string `json:"sources"`  // Breaches the credential appeared in
+	} `json:"result"`
+}
+
+// Breach represents a compromised credential found on Breach Directory.
+type Breach struct {
+	Password string `json:"password"` // Cracked or plaintext password
+	Sha1     string `json:"sha1"`     // SHA1 hash of the password
+	Sources  string `json:"sources"`  // Breaches the credential appeared in
+}
+
+// getJSON sends a GET request with any extra headers and decodes the JSON response into v.
+func (s *Searcher) getJSON(ctx context.Context, url string, header http.Header, v interface{}) error {
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Set request headers
+	userAgent := s.options.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+		if profile, err := s.headerProfile(Website{}); err == nil {
+			userAgent = profile.Headers.Get("User-Agent")
+		}
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept", "application/json")
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	// Send request
+	client := &http.Client{Timeout: s.options.Timeout, Transport: s.transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	// Parse JSON response
+	if err := sonic.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error parsing JSON: %w", err)
+	}
+	return nil
+}
+
+// HudsonRock searches HudsonRock's database for info-stealer compromises.
+func (s *Searcher) HudsonRock(ctx context.Context, username string) (HudsonRockResponse, error) {
+	// The response holds passwords taken from infected computers; keep it off the disk
+	var response HudsonRockResponse
+	err := s.getJSON(withSecrets(ctx), s.options.Endpoints.HudsonRock+"?username="+url.QueryEscape(username), nil, &response)
+	return response, err
+}
+
+// SearchProxyNova checks ProxyNova for compromised passwords associated with the username.
+func (s *Searcher) SearchProxyNova(ctx context.Context, username string) (ProxyNova, error) {
+	// The response holds breached email and password pairs; keep it off the disk
+	var response ProxyNova
+	err := s.getJSON(withSecrets(ctx), s.options.Endpoints.ProxyNova+"?query="+url.QueryEscape(username), nil, &response)
+	return response, err
+}
+
+// SearchBreachDirectory searches Breach Directory for compromised credentials using an API key.
+// Password hashes are cracked with Weakpass where possible.
+func (s *Searcher) SearchBreachDirectory(ctx context.Context, username string, apikey string) ([]Breach, error) {
+	if apikey == "" {
+		return nil, fmt.Errorf("API key cannot be empty")
+	}
+
+	// The request carries the API key and the response holds breached passwords; keep both off the disk
+	ctx = withSecrets(ctx)
+
+	// Search for breaches
+	header := http.Header{}
+	header.Set("x-rapidapi-key", apikey)
+	header.Set("x-rapidapi-host", "breachdirectory.p.rapidapi.com")
+	var response breachDirectoryResponse
+	if err := s.getJSON(ctx, s.options.Endpoints.BreachDirectory+"?func=auto&term="+url.QueryEscape(username), header, &response); err != nil {
+		return nil, err
+	}
+
+	breaches := make([]Breach, 0, len(response.Result))
+	for _, entry := range response.Result {
+		// Attempt to crack hash, falling back to the reported password
+		password := entry.Password
+		if pass, err := s.CrackHash(ctx, entry.Hash); err == nil && pass != "" {
+			password = pass
+		}
+
+		breaches = append(breaches, Breach{
+			Password: password,
+			Sha1:     entry.Sha1,
+			Sources:  entry.Sources,
+		})
+	}
+
+	return breaches, nil
+}
+
+// CrackHash attempts to crack a password hash using the Weakpass API.
+func (s *Searcher) CrackHash(ctx context.Context, hash string) (string, error) {
+	var weakpass WeakpassResponse
+	if err := s.getJSON(withoutCache(ctx), fmt.Sprintf("%s/%s.json", s.options.Endpoints.Weakpass, url.PathEscape(hash)), nil, &weakpass); err != nil {
+		return "", err
+	}
+	// Return cracked password
+	return weakpass.Pass, nil
+}
@@ -0,0 +1,180 @@
+package gosearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/bytedance/sonic"
+)
+
+// testAPIKey is the Breach Directory API key the mock accepts.
+const testAPIKey = "test-key"
+
+// newBreachServer starts a server mimicking the breach database APIs, returning endpoints pointing at it.
+func newBreachServer(t *testing.T) Endpoints {
+	t.Helper()
+	server := httptest.NewServer(breachHandler())
+	t.Cleanup(server.Close)
+	return breachEndpoints(server.URL)
+}
+
+// breachEndpoints returns endpoints for the breach APIs served by breachHandler at base.
+func breachEndpoints(base string) Endpoints {
+	return Endpoints{
+		HudsonRock:      base + "/hudsonrock",
+		ProxyNova:       base + "/proxynova",
+		BreachDirectory: base + "/breachdirectory/",
+		Weakpass:        base + "/weakpass",
+	}
+}
+
+// breachHandler mimics the HudsonRock, ProxyNova, Breach Directory and Weakpass APIs.
+// Only existingUser has breached credentials.
+func breachHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /hudsonrock", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("username") != existingUser {
+			writeTestJSON(w, HudsonRockResponse{Message: hudsonRockNotFound, Stealers: []Stealer{}})
+			return
+		}
+		writeTestJSON(w, HudsonRockResponse{
+			Message: "This username is associated with a computer that was infected by an info-stealer.",
+			Stealers: []Stealer{{
+				StealerFamily: "RedLine",
+				ComputerName:  "DESKTOP-1",
+				TopPasswords:  []string{"hunter2"},
+			}},
+		})
+	})
+
+	mux.HandleFunc("GET /proxynova", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("query") != existingUser {
+			writeTestJSON(w, ProxyNova{Lines: []string{}})
+			return
+		}
+		writeTestJSON(w, ProxyNova{Count: 2, Lines: []string{"alice@example.com:hunter2", "alice:letmein"}})
+	})
+
+	mux.HandleFunc("GET /breachdirectory/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-rapidapi-key") != testAPIKey {
+			w.WriteHeader(http.StatusUnauthorized)
+			io.WriteString(w, `{"message":"Invalid API key"}`)
+			return
+		}
+		if r.URL.Query().Get("func") != "auto" || r.URL.Query().Get("term") != existingUser {
+			writeTestJSON(w, breachDirectoryResponse{})
+			return
+		}
+		io.WriteString(w, `{"found":2,"result":[`+
+			`{"email":"alice@example.com","password":"hun****","sha1":"sha-cracked","hash":"cracked","sources":"Example"},`+
+			`{"email":"alice","password":"let****","sha1":"sha-uncracked","hash":"uncracked","sources":"Other"}]}`)
+	})
+
+	mux.HandleFunc("GET /weakpass/{file}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("file") != "cracked.json" {
+			http.NotFound(w, r)
+			return
+		}
+		writeTestJSON(w, WeakpassResponse{Type: "md5", Hash: "cracked", Pass: "hunter2"})
+	})
+
+	return mux
+}
+
+// writeTestJSON writes v as a JSON response.
+func writeTestJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	body, _ := sonic.Marshal(v)
+	w.Write(body)
+}
+
+func TestHudsonRock(t *testing.T) {
+	endpoints := newBreachServer(t)
+	searcher := newTestSearcher(Data{}, Options{Endpoints: endpoints})
+
+	response, err := searcher.HudsonRock(context.Background(), existingUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !response.Compromised() || len(response.Stealers) != 1 || response.Stealers[0].StealerFamily != "RedLine" {
+		t.Errorf("compromised user: got %+v", response)
+	}
+
+	response, err = searcher.HudsonRock(context.Background(), missingUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Compromised() {
+		t.Errorf("clean user reported as compromised: %+v", response)
+	}
+}
+
+func TestSearchProxyNova(t *testing.T) {
+	endpoints := newBreachServer(t)
+	searcher := newTestSearcher(Data{}, Options{Endpoints: endpoints})
+
+	response, err := searcher.SearchProxyNova(context.Background(), existingUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ProxyNova{Count: 2, Lines: []string{"alice@example.com:hunter2", "alice:letmein"}}
+	if !reflect.DeepEqual(response, want) {
+		t.Errorf("got %+v, want %+v", response, want)
+	}
+
+	response, err = searcher.SearchProxyNova(context.Background(), missingUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.Count != 0 {
+		t.Errorf("clean user: got %+v", response)
+	}
+}
+
+func TestSearchBreachDirectory(t *testing.T) {
+	endpoints := newBreachServer(t)
+	searcher := newTestSearcher(Data{}, Options{Endpoints: endpoints})
+
+	// Cracked hashes replace the masked password; uncracked ones keep it
+	breaches, err := searcher.SearchBreachDirectory(context.Background(), existingUser, testAPIKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Breach{
+		{Password: "hunter2", Sha1: "sha-cracked", Sources: "Example"},
+		{Password: "let****", Sha1: "sha-uncracked", Sources: "Other"},
+	}
+	if !reflect.DeepEqual(breaches, want) {
+		t.Errorf("got %+v, want %+v", breaches, want)
+	}
+
+	breaches, err = searcher.SearchBreachDirectory(context.Background(), missingUser, testAPIKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breaches) != 0 {
+		t.Errorf("clean user: got %+v", breaches)
+	}
+
+	if _, err := searcher.SearchBreachDirectory(context.Background(), existingUser, ""); err == nil {
+		t.Error("empty API key: expected an error")
+	}
+}
+
+func TestCrackHash(t *testing.T) {
+	endpoints := newBreachServer(t)
+	searcher := newTestSearcher(Data{}, Options{Endpoints: endpoints})
+
+	password, err := searcher.CrackHash(context.Background(), "cracked")
+	if err != nil || password != "hunter2" {
+		t.Errorf("got %q, %v; want %q", password, err, "hunter2")
+	}
+	if _, err := searcher.CrackHash(context.Background(), "uncracked"); err == nil {
+		t.Error("uncracked hash: expected an error")
+	}
+}
@@ -0,0 +1,165 @@
+package gosearch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached responses are served by default.
+const DefaultCacheTTL = time.Hour
+
+// Cache is an on-disk HTTP response cache keyed by request method and URL.
+// Only GET and HEAD requests are cached, and responses with a 429 or 5xx status are never stored.
+// Requests carrying site secrets bypass the cache so authenticated responses are not written to disk.
+// A Cache is safe for concurrent use and may be shared between Searchers.
+type Cache struct {
+	dir string
+	ttl time.Duration
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCache creates a cache storing responses in dir for ttl, creating dir if needed.
+func NewCache(dir string, ttl time.Duration) (*Cache, error) {
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating cache directory: %w", err)
+	}
+	return &Cache{dir: dir, ttl: ttl}, nil
+}
+
+// Hits returns how many responses the cache has served.
+func (c *Cache) Hits() int {
+	return int(c.hits.Load())
+}
+
+// Misses returns how many cacheable requests were sent to the network.
+func (c *Cache) Misses() int {
+	return int(c.misses.Load())
+}
+
+// responseFile returns the name of the file storing responses to the request, derived from its method and URL.
+func responseFile(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	return hex.EncodeToString(sum[:]) + ".http"
+}
+
+// readResponse reads a response stored by writeResponse.
+func readResponse(path string, req *http.Request) (*http.Response, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req)
+}
+
+// bufferBody reads a response body into memory so the response can be both stored and returned.
+func bufferBody(res *http.Response) error {
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	res.ContentLength = int64(len(body))
+	res.TransferEncoding = nil
+	return nil
+}
+
+// writeResponse stores a response whose body has been buffered in the file at path.
+func writeResponse(path string, res *http.Response) error {
+	data, err := httputil.DumpResponse(res, true)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so concurrent readers never see a partial response
+	tmp, err := os.CreateTemp(filepath.Dir(path), "*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// load returns the cached response to the request, or nil if there is no fresh cached response.
+func (c *Cache) load(req *http.Request) *http.Response {
+	path := filepath.Join(c.dir, responseFile(req))
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return nil
+	}
+
+	res, err := readResponse(path, req)
+	if err != nil {
+		return nil
+	}
+	return res
+}
+
+// noCacheKey is the context key marking requests that must not be cached.
+type noCacheKey struct{}
+
+// withoutCache returns a context whose requests bypass the cache.
+func withoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+// Transport returns a round tripper that serves cached responses and caches responses from next.
+func (c *Cache) Transport(next http.RoundTripper) http.RoundTripper {
+	return &cacheTransport{cache: c, next: next}
+}
+
+// cacheTransport serves requests from a Cache, falling back to the network.
+type cacheTransport struct {
+	cache *Cache
+	next  http.RoundTripper
+}
+
+// RoundTrip serves the request from the cache if possible, otherwise sends it and caches the response.
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || req.Context().Value(noCacheKey{}) != nil {
+		return t.next.RoundTrip(req)
+	}
+
+	if res := t.cache.load(req); res != nil {
+		t.cache.hits.Add(1)
+		return res, nil
+	}
+	t.cache.misses.Add(1)
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil || res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500 {
+		return res, err
+	}
+
+	if err := bufferBody(res); err != nil {
+		return nil, err
+	}
+
+	// A response that cannot be cached is still returned; the next run fetches it again
+	writeResponse(filepath.Join(t.cache.dir, responseFile(req)), res)
+	return res, nil
+}
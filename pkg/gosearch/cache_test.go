@@ -0,0 +1,77 @@
+package gosearch
+
+import (
+	"context"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// cachedFiles counts the files in a cache directory.
+func cachedFiles(t *testing.T, dir string) int {
+	t.Helper()
+	count := 0
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			count++
+		}
+		return nil
+	})
+	return count
+}
+
+func TestCacheSkipsSecrets(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewCache(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Breach Directory requests carry the API key and their responses breached passwords
+	searcher := newTestSearcher(Data{}, Options{Endpoints: newBreachServer(t), Cache: cache})
+	if _, err := searcher.SearchBreachDirectory(context.Background(), existingUser, testAPIKey); err != nil {
+		t.Fatal(err)
+	}
+	if n := cachedFiles(t, dir); n != 0 {
+		t.Errorf("got %d cached Breach Directory responses, want none", n)
+	}
+
+	// Every search needs a fresh session token
+	bootstraps := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bootstrap":
+			bootstraps++
+			w.Write([]byte(`<meta name="csrf" content="token-` + strconv.Itoa(bootstraps) + `">`))
+		case "/probe":
+			if r.URL.Query().Get("token") != "token-"+strconv.Itoa(bootstraps) {
+				http.Error(w, "stale token", http.StatusForbidden)
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	website := Website{
+		Name:      "session",
+		BaseURL:   server.URL + "/{}",
+		URLProbe:  server.URL + "/probe?user={}&token={token}",
+		ErrorType: "status_code",
+		ErrorCode: http.StatusNotFound,
+		Bootstrap: &Bootstrap{URL: server.URL + "/bootstrap", TokenRegex: `content="([^"]+)"`},
+	}
+	searcher = newTestSearcher(Data{}, Options{Cache: cache})
+	for i := 0; i < 2; i++ {
+		if result := searcher.SearchWebsite(context.Background(), website, existingUser); result.Status != StatusFound {
+			t.Errorf("search %d: got %+v, want found", i+1, result)
+		}
+	}
+	if bootstraps != 2 {
+		t.Errorf("got %d bootstrap requests, want one per search", bootstraps)
+	}
+	if n := cachedFiles(t, dir); n != 0 {
+		t.Errorf("got %d cached session responses, want none", n)
+	}
+}
@@ -0,0 +1,233 @@
+package gosearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrorTypes lists the error types a website can use to detect profiles.
+var ErrorTypes = []string{"status_code", "errorMsg", "profilePresence", "response_url", "unknown"}
+
+// Validate checks that a website entry is complete and that its settings are understood by GoSearch.
+func (w Website) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if strings.TrimSpace(w.Name) == "" {
+		fail("name is required")
+	} else if w.Name != strings.TrimSpace(w.Name) {
+		fail("name %q has leading or trailing spaces", w.Name)
+	}
+
+	// A disabled entry only names the website it removes
+	if w.Disabled {
+		return errors.Join(errs...)
+	}
+
+	if err := validateTemplate(w.BaseURL); err != nil {
+		fail("base_url: %w", err)
+	}
+	if w.URLProbe != "" {
+		if err := validateTemplate(w.URLProbe); err != nil {
+			fail("url_probe: %w", err)
+		}
+	}
+
+	// Check the fields each error type relies on
+	switch w.ErrorType {
+	case "status_code":
+		if w.ErrorCode != 0 && (w.ErrorCode < 100 || w.ErrorCode > 599) {
+			fail("errorCode %d is not an HTTP status code", w.ErrorCode)
+		}
+	case "errorMsg", "profilePresence":
+		if w.ErrorMsg == "" {
+			fail("errorMsg is required for errorType %s", w.ErrorType)
+		}
+	case "response_url":
+		if w.ResponseURL == "" {
+			fail("response_url is required for errorType response_url")
+		} else if parsed, err := url.Parse(strings.ReplaceAll(w.ResponseURL, "{}", "username")); err != nil || !parsed.IsAbs() {
+			fail("response_url %q is not an absolute URL", w.ResponseURL)
+		}
+	case "unknown":
+	default:
+		fail("errorType %q is not one of %s", w.ErrorType, strings.Join(ErrorTypes, ", "))
+	}
+
+	// Websites with errorType unknown may keep what was observed while investigating them
+	if w.ErrorType != "unknown" {
+		if w.ErrorCode != 0 && w.ErrorType != "status_code" {
+			fail("errorCode is only used with errorType status_code")
+		}
+		if w.ErrorMsg != "" && w.ErrorType != "errorMsg" && w.ErrorType != "profilePresence" {
+			fail("errorMsg is only used with errorType errorMsg or profilePresence")
+		}
+		if w.ResponseURL != "" && w.ErrorType != "response_url" {
+			fail("response_url is only used with errorType response_url")
+		}
+	}
+
+	if w.Category != "" && !slices.Contains(Categories, w.Category) {
+		fail("category %q is not one of %s", w.Category, strings.Join(Categories, ", "))
+	}
+	for _, tag := range w.Tags {
+		if tag == "" || tag != strings.ToLower(tag) || strings.ContainsAny(tag, " ,") {
+			fail("tag %q must be lowercase, without spaces or commas", tag)
+		}
+	}
+	if w.HasTag("adult") && !w.NSFW {
+		fail("adult websites must be marked nsfw")
+	}
+
+	for _, cookie := range w.Cookies {
+		if cookie.Name == "" {
+			fail("cookies: every cookie needs a name")
+		}
+	}
+	if w.Proxy != "" {
+		if _, err := ParseProxy(w.Proxy); err != nil {
+			fail("proxy: %w", err)
+		}
+	}
+	if w.HeaderProfile != "" {
+		if _, err := LookupHeaderProfile(w.HeaderProfile); err != nil {
+			fail("header_profile: %w", err)
+		}
+	}
+	if w.TLS != nil {
+		if err := configureTLS(NewTransport(nil), *w.TLS, false, false); err != nil {
+			fail("tls: %w", err)
+		}
+	}
+	if w.Bootstrap != nil {
+		if err := validateURL(w.Bootstrap.URL); err != nil {
+			fail("bootstrap url: %w", err)
+		}
+		if w.Bootstrap.TokenRegex != "" {
+			if re, err := regexp.Compile(w.Bootstrap.TokenRegex); err != nil {
+				fail("bootstrap token_regex: %w", err)
+			} else if re.NumSubexp() < 1 {
+				fail("bootstrap token_regex needs a group capturing the token")
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s: %w", w.Name, errors.Join(errs...))
+	}
+	return nil
+}
+
+// validateURL checks that a URL, which may contain the {} placeholder, is an absolute HTTP or HTTPS URL.
+func validateURL(template string) error {
+	if template == "" {
+		return errors.New("URL is required")
+	}
+	parsed, err := url.Parse(strings.ReplaceAll(template, "{}", "username"))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%q is not an absolute http or https URL", template)
+	}
+	return nil
+}
+
+// validateTemplate checks that a URL template is an absolute HTTP or HTTPS URL containing the {} placeholder.
+func validateTemplate(template string) error {
+	if err := validateURL(template); err != nil {
+		return err
+	}
+	if !strings.Contains(template, "{}") {
+		return fmt.Errorf("%q has no {} placeholder for the username", template)
+	}
+	return nil
+}
+
+// Validate checks every website in the catalog and that no two websites share a name.
+func (d Data) Validate() error {
+	var errs []error
+	seen := map[string]bool{}
+	for _, website := range d.Websites {
+		if err := website.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+		key := strings.ToLower(website.Name)
+		if seen[key] {
+			errs = append(errs, fmt.Errorf("%s: more than one website has this name", website.Name))
+		}
+		seen[key] = true
+	}
+	return errors.Join(errs...)
+}
+
+// Index returns the index of the website with the name, compared case-insensitively, or -1 if there is none.
+func (d Data) Index(name string) int {
+	for i, website := range d.Websites {
+		if strings.EqualFold(website.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Sort sorts the websites by name, case-insensitively.
+func (d Data) Sort() {
+	sort.SliceStable(d.Websites, func(i, j int) bool {
+		return strings.ToLower(d.Websites[i].Name) < strings.ToLower(d.Websites[j].Name)
+	})
+}
+
+// FormatData formats a catalog as data.json: indented JSON with the websites sorted by name.
+func FormatData(data Data) ([]byte, error) {
+	sorted := Data{Name: data.Name, Websites: append([]Website(nil), data.Websites...)}
+	sorted.Sort()
+	return formatJSON(sorted)
+}
+
+// FormatWebsite formats a single website entry the way it appears in data.json.
+func FormatWebsite(website Website) ([]byte, error) {
+	return formatJSON(website)
+}
+
+// formatJSON encodes v as indented JSON, keeping HTML in error messages readable instead of escaping it.
+func formatJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("error formatting catalog: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Verify checks a website entry the way a search would: the existing username must be found and the
+// missing username must not be. Websites with errorType unknown cannot be verified.
+func (s *Searcher) Verify(ctx context.Context, website Website, existing, missing string) error {
+	if website.ErrorType == "unknown" {
+		return errors.New("websites with errorType unknown cannot be verified")
+	}
+	if result := s.SearchWebsite(ctx, website, existing); result.Status != StatusFound {
+		return fmt.Errorf("expected %s to be found, got %s%s", existing, result.Status, resultError(result))
+	}
+	if result := s.SearchWebsite(ctx, website, missing); result.Status != StatusNotFound {
+		return fmt.Errorf("expected %s not to be found, got %s%s", missing, result.Status, resultError(result))
+	}
+	return nil
+}
+
+// resultError formats a result's error for appending to a message, or returns an empty string if it has none.
+func resultError(result Result) string {
+	if result.Error == "" {
+		return ""
+	}
+	return " (" + result.Error + ")"
+}
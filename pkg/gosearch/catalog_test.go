@@ -0,0 +1,135 @@
+package gosearch
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestWebsiteValidate(t *testing.T) {
+	valid := Website{Name: "Example", BaseURL: "https://example.com/{}", ErrorType: "status_code"}
+
+	tests := []struct {
+		name   string
+		change func(w *Website)
+		want   string // substring of the error, or empty for a valid entry
+	}{
+		{"valid", func(w *Website) {}, ""},
+		{"missing name", func(w *Website) { w.Name = " " }, "name is required"},
+		{"no placeholder", func(w *Website) { w.BaseURL = "https://example.com/" }, "no {} placeholder"},
+		{"relative url", func(w *Website) { w.BaseURL = "/u/{}" }, "not an absolute http or https URL"},
+		{"bad probe", func(w *Website) { w.URLProbe = "ftp://example.com/{}" }, "url_probe"},
+		{"bad error type", func(w *Website) { w.ErrorType = "status" }, "is not one of"},
+		{"bad error code", func(w *Website) { w.ErrorCode = 42 }, "not an HTTP status code"},
+		{"missing error message", func(w *Website) { w.ErrorType = "errorMsg" }, "errorMsg is required"},
+		{"missing response url", func(w *Website) { w.ErrorType = "response_url" }, "response_url is required"},
+		{"stray error message", func(w *Website) { w.ErrorMsg = "Not found" }, "errorMsg is only used"},
+		{"unknown keeps notes", func(w *Website) { w.ErrorType, w.ErrorCode = "unknown", 200 }, ""},
+		{"bad header profile", func(w *Website) { w.HeaderProfile = "netscape" }, "header_profile"},
+		{"bad proxy", func(w *Website) { w.Proxy = "gopher://proxy" }, "proxy"},
+		{"adult category", func(w *Website) { w.Category = "adult" }, "must be marked nsfw"},
+		{"adult tag", func(w *Website) { w.Tags = []string{"adult"} }, "must be marked nsfw"},
+		{"adult nsfw", func(w *Website) { w.Category, w.NSFW = "adult", true }, ""},
+		{"token without group", func(w *Website) { w.Bootstrap = &Bootstrap{URL: "https://example.com/", TokenRegex: "token"} }, "capturing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			website := valid
+			tt.change(&website)
+			err := website.Validate()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("got error %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDataValidateDuplicates(t *testing.T) {
+	data := Data{Websites: []Website{
+		{Name: "Example", BaseURL: "https://example.com/{}", ErrorType: "status_code"},
+		{Name: "example", BaseURL: "https://example.org/{}", ErrorType: "status_code"},
+	}}
+	if err := data.Validate(); err == nil || !strings.Contains(err.Error(), "more than one website") {
+		t.Errorf("got error %v, want a duplicate name", err)
+	}
+}
+
+// TestCatalogFile checks that the bundled catalog is valid and formatted as the catalog commands write it.
+func TestCatalogFile(t *testing.T) {
+	content, err := os.ReadFile("../../data.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ParseData(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := data.Validate(); err != nil {
+		t.Errorf("data.json is not valid:\n%v", err)
+	}
+	formatted, err := FormatData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(formatted) != string(content) {
+		t.Error("data.json is not sorted and formatted; run it through gosearch catalog")
+	}
+	if len(data.Filter(Filter{Tags: []string{"adult"}, IncludeNSFW: true}).Websites) == 0 {
+		t.Error("no website in data.json is tagged adult, so --exclude-tags adult would exclude nothing")
+	}
+
+	// The upstream catalog is only loaded with a valid signature from the upstream key
+	signature, err := os.ReadFile("../../data.json" + SignatureSuffix)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifySignature(content, signature, DefaultCatalogTrust().Keys); err != nil {
+		t.Errorf("data.json%s does not match data.json; sign it again with minisign -Sm data.json: %v", SignatureSuffix, err)
+	}
+}
+
+func TestFormatData(t *testing.T) {
+	data := Data{Websites: []Website{
+		{Name: "beta", BaseURL: "https://beta.test/{}", ErrorType: "errorMsg", ErrorMsg: "<title>Not Found</title>"},
+		{Name: "Alpha", BaseURL: "https://alpha.test/{}", ErrorType: "status_code"},
+	}}
+	formatted, err := FormatData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(formatted)
+	if strings.Index(got, "Alpha") > strings.Index(got, "beta") {
+		t.Error("websites are not sorted by name")
+	}
+	if !strings.Contains(got, `"errorMsg": "<title>Not Found</title>"`) {
+		t.Errorf("HTML in errorMsg was escaped:\n%s", got)
+	}
+	if data.Websites[0].Name != "beta" {
+		t.Error("FormatData reordered the catalog passed to it")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	server := newFixtureServer(t)
+	searcher := newTestSearcher(Data{}, Options{})
+	ctx := context.Background()
+
+	website := Website{Name: "status", BaseURL: server.URL + "/status/{}", ErrorType: "status_code"}
+	if err := searcher.Verify(ctx, website, existingUser, missingUser); err != nil {
+		t.Errorf("correct entry: %v", err)
+	}
+
+	website = Website{Name: "message", BaseURL: server.URL + "/message/{}", ErrorType: "errorMsg", ErrorMsg: "Welcome"}
+	if err := searcher.Verify(ctx, website, existingUser, missingUser); err == nil {
+		t.Error("wrong errorMsg: expected an error")
+	}
+
+	website.ErrorType = "unknown"
+	if err := searcher.Verify(ctx, website, existingUser, missingUser); err == nil {
+		t.Error("errorType unknown: expected an error")
+	}
+}
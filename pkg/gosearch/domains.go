@@ -0,0 +1,431 @@
+package gosearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+)
+
+// Domain statuses reported by CheckDomain.
+const (
+	DomainUnregistered = "unregistered"      // No registration found
+	DomainParked       = "registered-parked" // Registered but not serving a website, or parked or for sale
+	DomainLive         = "registered-live"   // Registered and serving a website
+	DomainRedirecting  = "redirecting"       // Registered and redirecting to another domain
+	DomainUnknown      = "unknown"           // Registration could not be determined
+)
+
+// DefaultTLDs are the common top-level domains checked for a username.
+var DefaultTLDs = []string{
+	"com",
+	"net",
+	"org",
+	"biz",
+	"info",
+	"name",
+	"pro",
+	"cat",
+	"co",
+	"me",
+	"io",
+	"tech",
+	"dev",
+	"app",
+	"shop",
+	"fail",
+	"xyz",
+	"blog",
+	"portfolio",
+	"store",
+	"online",
+	"about",
+	"space",
+	"lol",
+	"fun",
+	"social",
+}
+
+// Defaults for checking domains.
+const (
+	DefaultDomainTimeout     = 30 * time.Second // Time limit for every lookup of a single domain
+	DefaultDomainConcurrency = 8                // Domains checked at once
+)
+
+// maxDomainRedirects is how many redirect responses stop a domain's website check, like http.Client's default policy.
+const maxDomainRedirects = 10
+
+// maxDomainBody is how much of a domain's page is read to recognise parking pages.
+const maxDomainBody = 1 << 20
+
+// DNS record types looked up for each domain.
+const (
+	dnsA    = 1
+	dnsNS   = 2
+	dnsMX   = 15
+	dnsAAAA = 28
+)
+
+// dnsNXDomain is the DNS response code for a name that does not exist.
+const dnsNXDomain = 3
+
+// DomainResult describes what was found for a domain.
+type DomainResult struct {
+	Domain     string   `json:"domain"`                // Checked domain
+	Status     string   `json:"status"`                // One of the Domain* statuses
+	Addresses  []string `json:"addresses,omitempty"`   // A and AAAA records
+	MX         []string `json:"mx,omitempty"`          // Mail servers
+	NS         []string `json:"ns,omitempty"`          // Name servers
+	Registrar  string   `json:"registrar,omitempty"`   // Registrar reported by RDAP
+	Registered string   `json:"registered,omitempty"`  // Registration date reported by RDAP
+	URL        string   `json:"url,omitempty"`         // First URL of the domain that answered
+	Redirects  []string `json:"redirects,omitempty"`   // URLs redirected to, in order
+	FinalURL   string   `json:"final_url,omitempty"`   // URL that finally answered
+	StatusCode int      `json:"status_code,omitempty"` // Status code of the final URL
+	Parked     string   `json:"parked,omitempty"`      // Why the domain was recognised as parked or for sale
+	Error      string   `json:"error,omitempty"`       // Why the registration could not be determined
+}
+
+// Found reports whether the domain serves a website, either its own or by redirecting to another domain.
+func (r DomainResult) Found() bool {
+	return r.Status == DomainLive || r.Status == DomainRedirecting
+}
+
+// dnsResponse is a DNS-over-HTTPS response in the JSON format served by Google and Cloudflare.
+type dnsResponse struct {
+	Status int `json:"Status"` // DNS response code
+	Answer []struct {
+		Type int    `json:"type"` // Record type
+		Data string `json:"data"` // Record data
+	} `json:"Answer"`
+}
+
+// rdapDomain is the part of an RDAP domain response describing the registration.
+type rdapDomain struct {
+	Entities []struct {
+		Roles      []string      `json:"roles"`
+		VcardArray []interface{} `json:"vcardArray"`
+	} `json:"entities"`
+	Events []struct {
+		Action string `json:"eventAction"`
+		Date   string `json:"eventDate"`
+	} `json:"events"`
+}
+
+// BuildDomains generates a list of potential domains using the username and the given TLDs,
+// or DefaultTLDs if none are given.
+func BuildDomains(username string, tlds []string) []string {
+	if len(tlds) == 0 {
+		tlds = DefaultTLDs
+	}
+
+	// Generate domains by appending TLDs to username
+	var domains []string
+	for _, tld := range tlds {
+		domains = append(domains, username+"."+tld)
+	}
+
+	return domains
+}
+
+// Domains returns the domains checked for the username, one per configured TLD.
+func (s *Searcher) Domains(username string) []string {
+	return BuildDomains(username, s.options.TLDs)
+}
+
+// ParseTLDs normalises TLDs to lowercase without a leading dot, dropping duplicates.
+// Multi-level suffixes such as co.uk are allowed.
+func ParseTLDs(values []string) ([]string, error) {
+	var tlds []string
+	for _, value := range values {
+		tld := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(value), "."))
+		if !validTLD(tld) {
+			return nil, fmt.Errorf("invalid TLD %q", value)
+		}
+		if !slices.Contains(tlds, tld) {
+			tlds = append(tlds, tld)
+		}
+	}
+	return tlds, nil
+}
+
+// LoadTLDs reads a file of TLDs, one per line. Blank lines and lines starting with # are ignored.
+func LoadTLDs(path string) ([]string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TLD file: %w", err)
+	}
+
+	var values []string
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		values = append(values, line)
+	}
+
+	tlds, err := ParseTLDs(values)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return tlds, nil
+}
+
+// validTLD reports whether tld is made of dot-separated labels of letters, digits and inner hyphens.
+func validTLD(tld string) bool {
+	if tld == "" {
+		return false
+	}
+	for _, label := range strings.Split(tld, ".") {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// CheckDomains checks the domains concurrently with CheckDomain, giving each domain DomainTimeout for all of
+// its lookups so a hanging host cannot stall the others. It returns the results in order and the errors for
+// domains whose registration could not be determined.
+func (s *Searcher) CheckDomains(ctx context.Context, domains []string) ([]DomainResult, []error) {
+	results := make([]DomainResult, len(domains))
+	slots := make(chan struct{}, s.options.DomainConcurrency)
+
+	var wg sync.WaitGroup
+	wg.Add(len(domains))
+	for i, domain := range domains {
+		go func(i int, domain string) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			domainCtx, cancel := context.WithTimeout(ctx, s.options.DomainTimeout)
+			defer cancel()
+			results[i] = s.CheckDomain(domainCtx, domain)
+		}(i, domain)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Status == DomainUnknown {
+			errs = append(errs, fmt.Errorf("error checking %s: %s", result.Domain, result.Error))
+		}
+	}
+	return results, errs
+}
+
+// CheckDomain resolves the domain's A, AAAA, MX and NS records, checks its registration with RDAP and requests
+// its website over HTTPS, then HTTP, following redirects. Every request goes through the Searcher's transport,
+// so DNS is resolved over HTTPS and honours the Searcher's proxies.
+func (s *Searcher) CheckDomain(ctx context.Context, domain string) DomainResult {
+	result := DomainResult{Domain: domain}
+
+	// Look up every record type; a name that does not exist has none of them
+	var dnsErrs []error
+	nxdomain := false
+	for _, recordType := range []int{dnsA, dnsAAAA, dnsMX, dnsNS} {
+		records, missing, err := s.lookupDNS(ctx, domain, recordType)
+		if err != nil {
+			dnsErrs = append(dnsErrs, err)
+			continue
+		}
+		nxdomain = nxdomain || missing
+		switch recordType {
+		case dnsA, dnsAAAA:
+			result.Addresses = append(result.Addresses, records...)
+		case dnsMX:
+			result.MX = records
+		case dnsNS:
+			result.NS = records
+		}
+	}
+	hasDNS := len(result.Addresses) > 0 || len(result.MX) > 0 || len(result.NS) > 0
+
+	registered, rdapErr := s.lookupRDAP(ctx, domain, &result)
+	switch {
+	case registered || hasDNS:
+	case rdapErr == nil || (nxdomain && len(dnsErrs) == 0):
+		// RDAP has no registration, or DNS says the name does not exist
+		result.Status = DomainUnregistered
+		return result
+	default:
+		result.Status = DomainUnknown
+		result.Error = errors.Join(append(dnsErrs, rdapErr)...).Error()
+		return result
+	}
+
+	// A registered domain without addresses cannot serve a website
+	result.Status = DomainParked
+	var body []byte
+	if len(result.Addresses) > 0 {
+		for _, scheme := range []string{"https://", "http://"} {
+			var ok bool
+			if body, ok = s.fetchDomain(ctx, scheme+domain, &result); ok {
+				break
+			}
+		}
+	}
+
+	// Parking and for-sale pages are not the username's website, whatever they serve,
+	// and neither are error pages: only a 2xx or 3xx answer counts as a website
+	result.Parked = s.options.ParkingSignatures.match(result, body)
+	if result.FinalURL == "" || result.Parked != "" || result.StatusCode < 200 || result.StatusCode >= 400 {
+		return result
+	}
+
+	result.Status = DomainLive
+	if final, err := url.Parse(result.FinalURL); err == nil && !sameDomain(final.Hostname(), domain) {
+		result.Status = DomainRedirecting
+	}
+	return result
+}
+
+// lookupDNS resolves records of one type for the domain over HTTPS. It reports whether the name does not exist.
+func (s *Searcher) lookupDNS(ctx context.Context, domain string, recordType int) ([]string, bool, error) {
+	query := fmt.Sprintf("%s?name=%s&type=%d", s.options.Endpoints.DNS, url.QueryEscape(domain), recordType)
+	var response dnsResponse
+	if err := s.getJSON(ctx, query, http.Header{"Accept": {"application/dns-json"}}, &response); err != nil {
+		return nil, false, fmt.Errorf("DNS lookup failed: %w", err)
+	}
+
+	var records []string
+	for _, answer := range response.Answer {
+		if answer.Type != recordType {
+			continue
+		}
+		record := strings.TrimSuffix(answer.Data, ".")
+		if recordType == dnsMX {
+			// MX data is "preference host"; only the host is kept
+			if _, host, ok := strings.Cut(record, " "); ok {
+				record = host
+			}
+		}
+		records = append(records, record)
+	}
+	return records, response.Status == dnsNXDomain, nil
+}
+
+// lookupRDAP checks the domain's registration with RDAP, recording the registrar and registration date in result.
+// It reports whether the domain is registered; a nil error with false means RDAP has no registration for it.
+func (s *Searcher) lookupRDAP(ctx context.Context, domain string, result *DomainResult) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.options.Endpoints.RDAP+"/"+domain, nil)
+	if err != nil {
+		return false, fmt.Errorf("error creating RDAP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/rdap+json")
+
+	client := &http.Client{Timeout: s.options.Timeout, Transport: s.transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("RDAP lookup failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("RDAP lookup failed, status code: %d", resp.StatusCode)
+	}
+
+	// The registration details are informative; a registered domain with an unreadable response is still registered
+	body, err := io.ReadAll(resp.Body)
+	var registration rdapDomain
+	if err != nil || sonic.Unmarshal(body, &registration) != nil {
+		return true, nil
+	}
+	for _, event := range registration.Events {
+		if event.Action == "registration" {
+			result.Registered = event.Date
+		}
+	}
+	for _, entity := range registration.Entities {
+		if slices.Contains(entity.Roles, "registrar") {
+			result.Registrar = vcardName(entity.VcardArray)
+		}
+	}
+	return true, nil
+}
+
+// vcardName returns the formatted name (fn) from a jCard, as used by RDAP entities.
+func vcardName(vcard []interface{}) string {
+	if len(vcard) < 2 {
+		return ""
+	}
+	properties, _ := vcard[1].([]interface{})
+	for _, property := range properties {
+		fields, _ := property.([]interface{})
+		if len(fields) == 4 && fields[0] == "fn" {
+			name, _ := fields[3].(string)
+			return name
+		}
+	}
+	return ""
+}
+
+// fetchDomain requests a URL of the domain, following redirects and recording them in result.
+// It returns the body of the final page and whether the website answered.
+func (s *Searcher) fetchDomain(ctx context.Context, target string, result *DomainResult) ([]byte, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, false
+	}
+	if profile, err := s.headerProfile(Website{}); err == nil {
+		profile.Apply(req, s.options.UserAgent)
+	}
+
+	var redirects []string
+	client := &http.Client{
+		Timeout:   s.options.Timeout,
+		Transport: s.transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxDomainRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxDomainRedirects)
+			}
+			redirects = append(redirects, req.URL.String())
+			return nil
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, false
+	}
+	defer resp.Body.Close()
+
+	result.URL = target
+	result.Redirects = redirects
+	result.FinalURL = resp.Request.URL.String()
+	result.StatusCode = resp.StatusCode
+
+	// The page is only needed to recognise parking pages; a website that answered counts even if it is unreadable
+	reader, err := decodeBody(resp)
+	if err != nil {
+		return nil, true
+	}
+	defer reader.Close()
+	body, _ := io.ReadAll(io.LimitReader(reader, maxDomainBody))
+	return body, true
+}
+
+// sameDomain reports whether host is the domain or one of its subdomains, such as its www. host.
+func sameDomain(host, domain string) bool {
+	host, domain = strings.ToLower(host), strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
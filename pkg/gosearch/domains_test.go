@@ -0,0 +1,271 @@
+package gosearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testZone is a domain known to lookupHandler.
+type testZone struct {
+	addresses []string // A records
+	ns        []string // NS records
+	registrar string   // Registrar reported by RDAP
+	broken    bool     // Whether DNS and RDAP fail for the domain
+}
+
+// lookupEndpoints returns endpoints for the DNS and RDAP APIs served by lookupHandler at base.
+func lookupEndpoints(base string) Endpoints {
+	return Endpoints{DNS: base + "/resolve", RDAP: base + "/domain"}
+}
+
+// lookupHandler mimics a DNS-over-HTTPS resolver and an RDAP service knowing only the given zones.
+func lookupHandler(zones map[string]testZone) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /resolve", func(w http.ResponseWriter, r *http.Request) {
+		zone, ok := zones[r.URL.Query().Get("name")]
+		if zone.broken {
+			http.Error(w, "resolver failure", http.StatusInternalServerError)
+			return
+		}
+		var response dnsResponse
+		if !ok {
+			response.Status = dnsNXDomain
+			writeTestJSON(w, response)
+			return
+		}
+		recordType, _ := strconv.Atoi(r.URL.Query().Get("type"))
+		records := map[int][]string{dnsA: zone.addresses, dnsNS: zone.ns}[recordType]
+		for _, record := range records {
+			response.Answer = append(response.Answer, struct {
+				Type int    `json:"type"`
+				Data string `json:"data"`
+			}{recordType, record + "."})
+		}
+		writeTestJSON(w, response)
+	})
+
+	mux.HandleFunc("GET /domain/{name}", func(w http.ResponseWriter, r *http.Request) {
+		zone, ok := zones[r.PathValue("name")]
+		switch {
+		case zone.broken:
+			http.Error(w, "registry failure", http.StatusInternalServerError)
+		case !ok:
+			http.NotFound(w, r)
+		default:
+			writeTestJSON(w, map[string]interface{}{
+				"entities": []interface{}{map[string]interface{}{
+					"roles":      []string{"registrar"},
+					"vcardArray": []interface{}{"vcard", []interface{}{[]interface{}{"fn", map[string]string{}, "text", zone.registrar}}},
+				}},
+				"events": []interface{}{map[string]string{"eventAction": "registration", "eventDate": "2020-01-01T00:00:00Z"}},
+			})
+		}
+	})
+
+	return mux
+}
+
+func TestCheckDomain(t *testing.T) {
+	zones := map[string]testZone{
+		"live.test":     {addresses: []string{"192.0.2.1"}, ns: []string{"ns1.live.test"}, registrar: "Test Registrar"},
+		"www.test":      {addresses: []string{"192.0.2.2"}},
+		"redirect.test": {addresses: []string{"192.0.2.3"}},
+		"parked.test":   {ns: []string{"ns1.parking.test"}},
+		"dead.test":     {addresses: []string{"192.0.2.4"}},
+		"broken.test":   {broken: true},
+		"parking.test":  {addresses: []string{"192.0.2.5"}, ns: []string{"ns1.sedoparking.com"}},
+		"forsale.test":  {addresses: []string{"192.0.2.6"}},
+		"market.test":   {addresses: []string{"192.0.2.7"}},
+		"hops.test":     {addresses: []string{"192.0.2.8"}},
+		"toomany.test":  {addresses: []string{"192.0.2.9"}},
+		"missing.test":  {addresses: []string{"192.0.2.10"}},
+		"down.test":     {addresses: []string{"192.0.2.11"}},
+		"huge.test":     {addresses: []string{"192.0.2.12"}},
+	}
+	lookup := lookupHandler(zones)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "lookup.test":
+			lookup.ServeHTTP(w, r)
+		case "live.test", "www.www.test":
+			w.Write([]byte("<html>" + r.Host + "</html>"))
+		case "www.test":
+			http.Redirect(w, r, "http://www.www.test/", http.StatusMovedPermanently)
+		case "redirect.test":
+			http.Redirect(w, r, "http://live.test/", http.StatusFound)
+		case "parking.test", "www.sedo.com":
+			w.Write([]byte("<html>" + r.Host + "</html>"))
+		case "forsale.test":
+			w.Write([]byte("<html><h1>This Domain Is For Sale!</h1></html>"))
+		case "hops.test", "toomany.test":
+			// Redirect through /1, /2, ... until the domain's redirect count is reached
+			hop, _ := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/"))
+			if limit := map[string]int{"hops.test": maxDomainRedirects - 1, "toomany.test": maxDomainRedirects}[r.Host]; hop < limit {
+				http.Redirect(w, r, "/"+strconv.Itoa(hop+1), http.StatusFound)
+				return
+			}
+			w.Write([]byte("<html>" + r.Host + "</html>"))
+		case "market.test":
+			http.Redirect(w, r, "http://www.sedo.com/search/details/?domain=market.test", http.StatusFound)
+		case "missing.test":
+			http.NotFound(w, r)
+		case "down.test":
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+		case "huge.test":
+			// Only the start of the page is read, so a marker past it is not seen
+			w.Write([]byte("<html>" + strings.Repeat(" ", maxDomainBody) + "This Domain Is For Sale!</html>"))
+		default:
+			panic(http.ErrAbortHandler)
+		}
+	}))
+	t.Cleanup(server.Close)
+	proxy, _ := url.Parse(server.URL)
+
+	searcher := newTestSearcher(Data{}, Options{Endpoints: lookupEndpoints("http://lookup.test"), Proxy: proxy})
+
+	tests := []struct {
+		domain string
+		status string
+	}{
+		{"live.test", DomainLive},
+		{"www.test", DomainLive},
+		{"redirect.test", DomainRedirecting},
+		{"parked.test", DomainParked},
+		{"dead.test", DomainParked},
+		{"parking.test", DomainParked},
+		{"forsale.test", DomainParked},
+		{"market.test", DomainParked},
+		{"hops.test", DomainLive},
+		{"toomany.test", DomainParked},
+		{"missing.test", DomainParked},
+		{"down.test", DomainParked},
+		{"huge.test", DomainLive},
+		{"unregistered.test", DomainUnregistered},
+		{"broken.test", DomainUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			result := searcher.CheckDomain(context.Background(), tt.domain)
+			if result.Status != tt.status {
+				t.Errorf("got status %q, want %q (%+v)", result.Status, tt.status, result)
+			}
+			if (tt.status == DomainUnknown) != (result.Error != "") {
+				t.Errorf("got error %q", result.Error)
+			}
+		})
+	}
+
+	result := searcher.CheckDomain(context.Background(), "live.test")
+	want := DomainResult{
+		Domain:     "live.test",
+		Status:     DomainLive,
+		Addresses:  []string{"192.0.2.1"},
+		NS:         []string{"ns1.live.test"},
+		Registrar:  "Test Registrar",
+		Registered: "2020-01-01T00:00:00Z",
+		URL:        "http://live.test",
+		FinalURL:   "http://live.test",
+		StatusCode: http.StatusOK,
+	}
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("got %+v, want %+v", result, want)
+	}
+
+	for domain, reason := range map[string]string{
+		"parking.test": "parking name server ns1.sedoparking.com",
+		"forsale.test": `page contains "this domain is for sale"`,
+		"market.test":  "redirects to marketplace sedo.com",
+		"dead.test":    "",
+	} {
+		if result := searcher.CheckDomain(context.Background(), domain); result.Parked != reason {
+			t.Errorf("%s: got parked %q, want %q", domain, result.Parked, reason)
+		}
+	}
+
+	result = searcher.CheckDomain(context.Background(), "redirect.test")
+	if want := []string{"http://live.test/"}; !reflect.DeepEqual(result.Redirects, want) || result.FinalURL != want[0] {
+		t.Errorf("got redirects %v to %s, want %v", result.Redirects, result.FinalURL, want)
+	}
+}
+
+func TestCheckDomainsTimeout(t *testing.T) {
+	zones := map[string]testZone{
+		"hang.test": {addresses: []string{"192.0.2.1"}},
+		"live.test": {addresses: []string{"192.0.2.2"}},
+	}
+	lookup := lookupHandler(zones)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "lookup.test":
+			lookup.ServeHTTP(w, r)
+		case "hang.test":
+			<-r.Context().Done()
+		case "live.test":
+			w.Write([]byte("<html>" + r.Host + "</html>"))
+		default:
+			panic(http.ErrAbortHandler)
+		}
+	}))
+	t.Cleanup(server.Close)
+	proxy, _ := url.Parse(server.URL)
+
+	searcher := newTestSearcher(Data{}, Options{
+		Endpoints:     lookupEndpoints("http://lookup.test"),
+		Proxy:         proxy,
+		DomainTimeout: 200 * time.Millisecond,
+	})
+
+	start := time.Now()
+	results, errs := searcher.CheckDomains(context.Background(), []string{"hang.test", "live.test", "none.test"})
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("took %s, want the hanging domain to time out", elapsed)
+	}
+	if len(errs) != 0 {
+		t.Errorf("got errors %v", errs)
+	}
+
+	var statuses []string
+	for _, result := range results {
+		statuses = append(statuses, result.Domain+" "+result.Status)
+	}
+	want := []string{"hang.test " + DomainParked, "live.test " + DomainLive, "none.test " + DomainUnregistered}
+	if !reflect.DeepEqual(statuses, want) {
+		t.Errorf("got %v, want %v", statuses, want)
+	}
+}
+
+func TestTLDs(t *testing.T) {
+	tlds, err := ParseTLDs([]string{".DE", "co.uk", "de", " fr "})
+	if want := []string{"de", "co.uk", "fr"}; err != nil || !reflect.DeepEqual(tlds, want) {
+		t.Errorf("ParseTLDs: got %v, %v, want %v", tlds, err, want)
+	}
+	for _, invalid := range []string{"", "co..uk", "-de", "d e", "de/"} {
+		if _, err := ParseTLDs([]string{invalid}); err == nil {
+			t.Errorf("ParseTLDs(%q): expected an error", invalid)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "tlds.txt")
+	os.WriteFile(path, []byte("# Regional TLDs\n.de\n\nfr\n"), 0o644)
+	tlds, err = LoadTLDs(path)
+	if want := []string{"de", "fr"}; err != nil || !reflect.DeepEqual(tlds, want) {
+		t.Errorf("LoadTLDs: got %v, %v, want %v", tlds, err, want)
+	}
+
+	if domains := BuildDomains("alice", []string{"de", "co.uk"}); !reflect.DeepEqual(domains, []string{"alice.de", "alice.co.uk"}) {
+		t.Errorf("BuildDomains: got %v", domains)
+	}
+	if domains := BuildDomains("alice", nil); len(domains) != len(DefaultTLDs) || domains[0] != "alice.com" {
+		t.Errorf("BuildDomains with default TLDs: got %v", domains)
+	}
+}
@@ -0,0 +1,107 @@
+package gosearch
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Categories lists the kinds of website a catalog entry can be filed under.
+var Categories = []string{
+	"social", "links", "development", "security", "forum", "gaming", "art", "music",
+	"video", "blog", "professional", "education", "shopping", "news", "adult", "other",
+}
+
+// Filter selects the websites a search covers. Site names and tags are compared case-insensitively,
+// and a website's category counts as one of its tags. Websites marked nsfw are only selected with IncludeNSFW.
+type Filter struct {
+	Sites        []string // Only search these websites, if set
+	ExcludeSites []string // Never search these websites
+	Tags         []string // Only search websites with at least one of these tags, if set
+	ExcludeTags  []string // Never search websites with any of these tags
+	IncludeNSFW  bool     // Also search websites marked nsfw
+}
+
+// Match reports whether the filter selects the website. Exclusions take precedence over inclusions.
+func (f Filter) Match(website Website) bool {
+	if website.NSFW && !f.IncludeNSFW {
+		return false
+	}
+	if containsFold(f.ExcludeSites, website.Name) {
+		return false
+	}
+	for _, tag := range f.ExcludeTags {
+		if website.HasTag(tag) {
+			return false
+		}
+	}
+	if len(f.Sites) > 0 && !containsFold(f.Sites, website.Name) {
+		return false
+	}
+	if len(f.Tags) > 0 && !slices.ContainsFunc(f.Tags, website.HasTag) {
+		return false
+	}
+	return true
+}
+
+// HasTag reports whether the website's category or one of its tags is tag, compared case-insensitively.
+func (w Website) HasTag(tag string) bool {
+	return strings.EqualFold(w.Category, tag) || containsFold(w.Tags, tag)
+}
+
+// Filter returns the websites the filter selects.
+func (d Data) Filter(filter Filter) Data {
+	filtered := Data{Websites: []Website{}}
+	for _, website := range d.Websites {
+		if filter.Match(website) {
+			filtered.Websites = append(filtered.Websites, website)
+		}
+	}
+	return filtered
+}
+
+// CheckFilter reports the site names and tags the filter asks for that no website in the catalog has,
+// and nsfw websites asked for by name that the filter would leave out.
+func (d Data) CheckFilter(filter Filter) error {
+	var errs []error
+	for _, name := range filter.Sites {
+		if d.Index(name) < 0 {
+			errs = append(errs, fmt.Errorf("no website is named %q", name))
+		}
+	}
+	for _, name := range filter.Sites {
+		if i := d.Index(name); i >= 0 && d.Websites[i].NSFW && !filter.IncludeNSFW {
+			errs = append(errs, fmt.Errorf("%s is marked nsfw and is only searched when nsfw websites are included", d.Websites[i].Name))
+		}
+	}
+	for _, tag := range filter.Tags {
+		if !slices.ContainsFunc(d.Websites, func(w Website) bool { return w.HasTag(tag) }) {
+			errs = append(errs, fmt.Errorf("no website has the tag %q", tag))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// FilterWarnings reports the site names and tags the filter excludes that no website in the catalog has.
+// Excluding them is harmless, since nothing is searched that would not be otherwise, but a misspelt exclusion
+// should not go unnoticed.
+func (d Data) FilterWarnings(filter Filter) []string {
+	var warnings []string
+	for _, name := range filter.ExcludeSites {
+		if d.Index(name) < 0 {
+			warnings = append(warnings, fmt.Sprintf("No website is named %q; excluding it has no effect.", name))
+		}
+	}
+	for _, tag := range filter.ExcludeTags {
+		if !slices.ContainsFunc(d.Websites, func(w Website) bool { return w.HasTag(tag) }) {
+			warnings = append(warnings, fmt.Sprintf("No website has the tag %q; excluding it has no effect.", tag))
+		}
+	}
+	return warnings
+}
+
+// containsFold reports whether list contains s, compared case-insensitively.
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(item string) bool { return strings.EqualFold(item, s) })
+}
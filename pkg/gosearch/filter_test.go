@@ -0,0 +1,68 @@
+package gosearch
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDataFilter(t *testing.T) {
+	data := Data{Websites: []Website{
+		{Name: "GitHub", Category: "development"},
+		{Name: "Lichess", Category: "gaming", Tags: []string{"chess"}},
+		{Name: "Chess", Category: "gaming", Tags: []string{"chess"}},
+		{Name: "Mastodon", Category: "social", Tags: []string{"fediverse"}},
+		{Name: "Dating", Category: "social", NSFW: true},
+	}}
+
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []string
+	}{
+		{"empty", Filter{}, []string{"GitHub", "Lichess", "Chess", "Mastodon"}},
+		{"sites", Filter{Sites: []string{"github", "CHESS"}}, []string{"GitHub", "Chess"}},
+		{"exclude sites", Filter{ExcludeSites: []string{"GitHub"}}, []string{"Lichess", "Chess", "Mastodon"}},
+		{"category", Filter{Tags: []string{"Gaming"}}, []string{"Lichess", "Chess"}},
+		{"any tag", Filter{Tags: []string{"development", "fediverse"}}, []string{"GitHub", "Mastodon"}},
+		{"exclude tags", Filter{ExcludeTags: []string{"chess", "social"}}, []string{"GitHub"}},
+		{"exclusion wins", Filter{Tags: []string{"gaming"}, ExcludeSites: []string{"Chess"}}, []string{"Lichess"}},
+		{"nsfw left out", Filter{Tags: []string{"social"}}, []string{"Mastodon"}},
+		{"nsfw included", Filter{Tags: []string{"social"}, IncludeNSFW: true}, []string{"Mastodon", "Dating"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := data.CheckFilter(tt.filter); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, website := range data.Filter(tt.filter).Websites {
+				got = append(got, website.Name)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	err := data.CheckFilter(Filter{Sites: []string{"Gitub"}, Tags: []string{"chees"}})
+	if err == nil || !strings.Contains(err.Error(), `"Gitub"`) || !strings.Contains(err.Error(), `"chees"`) {
+		t.Errorf("got error %v, want both misspellings reported", err)
+	}
+
+	// Unknown exclusions cannot let a website through, so they are only warned about
+	exclusions := Filter{ExcludeSites: []string{"Gitub"}, ExcludeTags: []string{"adult"}}
+	if err := data.CheckFilter(exclusions); err != nil {
+		t.Errorf("got error %v for unknown exclusions, want none", err)
+	}
+	warnings := data.FilterWarnings(exclusions)
+	if len(warnings) != 2 || !strings.Contains(warnings[0], `"Gitub"`) || !strings.Contains(warnings[1], `"adult"`) {
+		t.Errorf("got warnings %q, want both unknown exclusions reported", warnings)
+	}
+	if warnings := data.FilterWarnings(Filter{ExcludeTags: []string{"chess"}}); len(warnings) != 0 {
+		t.Errorf("got warnings %q for a known tag, want none", warnings)
+	}
+	if err := data.CheckFilter(Filter{Sites: []string{"dating"}}); err == nil || !strings.Contains(err.Error(), "nsfw") {
+		t.Errorf("got error %v, want the nsfw website reported", err)
+	}
+}
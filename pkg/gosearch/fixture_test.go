@@ -0,0 +1,138 @@
+package gosearch
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// existingUser is the only username the fixture server has a profile for.
+const existingUser = "alice"
+
+// missingUser is a username the fixture server has no profile for.
+const missingUser = "bob"
+
+// newFixtureServer starts a server mimicking how websites answer for existing and missing profiles.
+func newFixtureServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(fixtureHandler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fixtureHandler answers like websites do for existing and missing profiles.
+// Every route takes the username as its last path segment.
+func fixtureHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	// Missing profiles answer 404
+	mux.HandleFunc("GET /status/{user}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("user") != existingUser {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "<html>profile</html>")
+	})
+
+	// Missing profiles redirect to the home page, which a client not following redirects sees as a 302
+	mux.HandleFunc("GET /code/{user}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("user") != existingUser {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		io.WriteString(w, "<html>profile</html>")
+	})
+
+	// Missing profiles answer 200 with an error message
+	mux.HandleFunc("GET /message/{user}", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, profilePage(r.PathValue("user")))
+	})
+
+	// Existing profiles contain a marker that missing profiles lack
+	mux.HandleFunc("GET /presence/{user}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("user") != existingUser {
+			io.WriteString(w, `<html><div class="search">Search</div></html>`)
+			return
+		}
+		io.WriteString(w, `<html><div class="profile-header">alice</div></html>`)
+	})
+
+	// Missing profiles redirect to a not-found page that answers 200
+	mux.HandleFunc("GET /moved/{user}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("user") != existingUser {
+			http.Redirect(w, r, "/notfound", http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/status/"+existingUser, http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("GET /notfound", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html>Page not found</html>")
+	})
+
+	// Every profile redirects; missing ones to a search for the username
+	mux.HandleFunc("GET /profiles/{user}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("user") != existingUser {
+			http.Redirect(w, r, "/search?q="+r.PathValue("user"), http.StatusFound)
+			return
+		}
+		http.Redirect(w, r, "/status/"+existingUser, http.StatusFound)
+	})
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "<html>Search results</html>")
+	})
+
+	// Every request redirects, whether or not the profile exists
+	mux.HandleFunc("GET /always-redirect/{user}", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/status/"+r.PathValue("user"), http.StatusMovedPermanently)
+	})
+
+	// Bodies are compressed with the encoding named in the path
+	mux.HandleFunc("GET /compressed/{encoding}/{user}", func(w http.ResponseWriter, r *http.Request) {
+		var writer io.WriteCloser
+		switch encoding := r.PathValue("encoding"); encoding {
+		case "gzip":
+			writer = gzip.NewWriter(w)
+		case "deflate":
+			writer = zlib.NewWriter(w)
+		case "br":
+			writer = brotli.NewWriter(w)
+		default:
+			http.Error(w, "unknown encoding", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Encoding", r.PathValue("encoding"))
+		io.WriteString(writer, profilePage(r.PathValue("user")))
+		writer.Close()
+	})
+
+	// Profiles are only shown once a consent cookie is set
+	mux.HandleFunc("GET /cookie/{user}", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("consent"); err != nil || cookie.Value != "yes" {
+			http.Error(w, "consent required", http.StatusForbidden)
+			return
+		}
+		io.WriteString(w, profilePage(r.PathValue("user")))
+	})
+
+	return mux
+}
+
+// profilePage returns the fixture page for a username, containing "User not found" if it has no profile.
+func profilePage(user string) string {
+	if user != existingUser {
+		return "<html><h1>User not found</h1></html>"
+	}
+	return "<html><h1>" + user + "</h1></html>"
+}
+
+// newTestSearcher creates a Searcher with short timeouts and no retries for tests.
+func newTestSearcher(data Data, options Options) *Searcher {
+	options.Timeout = 5 * time.Second
+	return NewSearcher(data, options)
+}
@@ -0,0 +1,163 @@
+// Package gosearch implements GoSearch's search engine: searching usernames across websites, checking
+// breach databases for compromised credentials and finding domains associated with a username.
+//
+// The package performs no terminal output and keeps no global state; results are delivered to callers
+// through return values and callbacks so they can be rendered however the caller needs.
+package gosearch
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/bytedance/sonic"
+)
+
+// DefaultUserAgent is the User-Agent header used in HTTP requests to mimic a browser.
+const DefaultUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:140.0) Gecko/20100101 Firefox/140.0"
+
+// DataURL is the location of the latest website configuration, signed with UpstreamCatalogKey.
+const DataURL = "https://raw.githubusercontent.com/tkerby/gosearch/refs/heads/main/data.json"
+
+// Website represents a website configuration for searching usernames.
+type Website struct {
+	Name            string       `json:"name"`                     // Website name
+	BaseURL         string       `json:"base_url"`                 // Base URL template
+	URLProbe        string       `json:"url_probe,omitempty"`      // Optional probe URL
+	FollowRedirects bool         `json:"follow_redirects"`         // Whether to follow HTTP redirects
+	UserAgent       string       `json:"user_agent,omitempty"`     // Custom User-Agent, if any
+	ErrorType       string       `json:"errorType"`                // Type of error checking
+	ErrorMsg        string       `json:"errorMsg,omitempty"`       // Expected error message for non-existent profiles
+	ErrorCode       int          `json:"errorCode,omitempty"`      // Expected HTTP status code for non-existent profiles
+	ResponseURL     string       `json:"response_url,omitempty"`   // Expected response URL for existing profiles
+	Cookies         []Cookie     `json:"cookies,omitempty"`        // Cookies to include in requests
+	Proxy           string       `json:"proxy,omitempty"`          // Proxy for this website, overriding the global proxy
+	HeaderProfile   string       `json:"header_profile,omitempty"` // Header profile pinned for this website
+	TLS             *TLSSettings `json:"tls,omitempty"`            // TLS and protocol settings for this website
+	Bootstrap       *Bootstrap   `json:"bootstrap,omitempty"`      // Request establishing a session before the probe
+	Category        string       `json:"category,omitempty"`       // Kind of website, one of Categories
+	Tags            []string     `json:"tags,omitempty"`           // Further labels, such as a region or community
+	NSFW            bool         `json:"nsfw,omitempty"`           // Adult or otherwise sensitive website, only searched when asked for
+	Disabled        bool         `json:"disabled,omitempty"`       // In an overlay catalog, removes the website of the same name
+	Catalog         string       `json:"-"`                        // Name of the catalog the website was loaded from
+}
+
+// Data holds the list of websites to search.
+type Data struct {
+	Name     string    `json:"name,omitempty"` // Catalog name results are attributed to; defaults to the file name
+	Websites []Website `json:"websites"`       // List of website configurations
+}
+
+// Cookie represents an HTTP cookie.
+type Cookie struct {
+	Name  string `json:"name"`  // Cookie name
+	Value string `json:"value"` // Cookie value
+}
+
+// Result statuses reported for each searched website.
+const (
+	StatusFound      = "found"      // Profile exists
+	StatusUnverified = "unverified" // Profile may exist but could not be verified
+	StatusNotFound   = "not_found"  // Profile does not exist
+	StatusSkipped    = "skipped"    // Website skipped because its result could not be verified
+	StatusError      = "error"      // Request failed
+)
+
+// Result represents the outcome of searching a single website for a username.
+type Result struct {
+	Username string `json:"username"`          // Searched username
+	Website  string `json:"website"`           // Website name
+	URL      string `json:"url"`               // Profile URL
+	Status   string `json:"status"`            // One of the Status* constants
+	Error    string `json:"error,omitempty"`   // Error message if the request failed
+	NSFW     bool   `json:"nsfw,omitempty"`    // Whether the website is marked as adult or otherwise sensitive
+	Catalog  string `json:"catalog,omitempty"` // Name of the catalog the website came from
+}
+
+// Profile reports whether the result is a found or unverified profile.
+func (r Result) Profile() bool {
+	return r.Status == StatusFound || r.Status == StatusUnverified
+}
+
+// UnmarshalJSON fetches and parses the latest website configuration from DataURL. Overlay catalogs, given as
+// file paths or URLs, are merged on top of it in order with MergeData. Remote catalogs must be signed by
+// UpstreamCatalogKey, following DefaultCatalogTrust.
+func UnmarshalJSON(overlays ...string) (Data, error) {
+	trust := DefaultCatalogTrust()
+	data, err := FetchData(http.DefaultClient, trust)
+	if err != nil {
+		return Data{}, err
+	}
+	catalogs := []Data{data}
+	for _, source := range overlays {
+		overlay, err := LoadCatalog(http.DefaultClient, source, trust)
+		if err != nil {
+			return Data{}, err
+		}
+		catalogs = append(catalogs, overlay)
+	}
+	return MergeData(catalogs...), nil
+}
+
+// FetchData fetches and parses the latest website configuration from DataURL using client, verifying its
+// signature according to trust. Its websites are attributed to UpstreamCatalog.
+func FetchData(client *http.Client, trust CatalogTrust) (Data, error) {
+	data, err := fetchCatalog(client, DataURL, trust)
+	if err != nil {
+		return Data{}, err
+	}
+	return data.named(UpstreamCatalog), nil
+}
+
+// fetchCatalog fetches and parses a catalog in data.json format from url using client, verifying its
+// detached signature according to trust before parsing it.
+func fetchCatalog(client *http.Client, url string, trust CatalogTrust) (Data, error) {
+	jsonData, err := download(client, url)
+	if err != nil {
+		return Data{}, err
+	}
+	if err := verifyCatalog(client, url, jsonData, trust); err != nil {
+		return Data{}, fmt.Errorf("refusing to load %s: %w", url, err)
+	}
+	return ParseData(jsonData)
+}
+
+// download fetches the body of url using client.
+func download(client *http.Client, url string) ([]byte, error) {
+	// Fetch JSON from repository
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	// Check HTTP status
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download %s, status code: %d", url, resp.StatusCode)
+	}
+
+	// Read response body
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading downloaded content: %w", err)
+	}
+	return body, nil
+}
+
+// ParseData parses a website configuration in data.json format.
+func ParseData(jsonData []byte) (Data, error) {
+	// Unmarshal JSON into Data struct
+	var data Data
+	err := sonic.Unmarshal(jsonData, &data)
+	if err != nil {
+		return Data{}, fmt.Errorf("error unmarshalling JSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// BuildURL constructs a URL by replacing the placeholder with the username.
+func BuildURL(baseURL, username string) string {
+	return strings.Replace(baseURL, "{}", username, 1)
+}
@@ -0,0 +1,64 @@
+package gosearch
+
+// Handler consumes the event stream produced by Searcher.Run.
+// Terminal output, report files, notifications and API servers are all implemented as Handlers.
+// Methods are called from a single goroutine, in the order the events occur.
+type Handler interface {
+	StageStarted(stage string)                             // Called before a stage runs
+	SiteResult(result Result)                              // Called for every searched website
+	StageFinished(stage string, report *Report, err error) // Called after a stage, with its error if any
+	RunFinished(report *Report)                            // Called once every stage has finished
+}
+
+// Handlers combines several handlers into one that forwards every event to each handler in order.
+func Handlers(handlers ...Handler) Handler {
+	return multiHandler(handlers)
+}
+
+// multiHandler forwards events to a list of handlers.
+type multiHandler []Handler
+
+// StageStarted forwards the event to every handler.
+func (m multiHandler) StageStarted(stage string) {
+	for _, h := range m {
+		h.StageStarted(stage)
+	}
+}
+
+// SiteResult forwards the event to every handler.
+func (m multiHandler) SiteResult(result Result) {
+	for _, h := range m {
+		h.SiteResult(result)
+	}
+}
+
+// StageFinished forwards the event to every handler.
+func (m multiHandler) StageFinished(stage string, report *Report, err error) {
+	for _, h := range m {
+		h.StageFinished(stage, report, err)
+	}
+}
+
+// RunFinished forwards the event to every handler.
+func (m multiHandler) RunFinished(report *Report) {
+	for _, h := range m {
+		h.RunFinished(report)
+	}
+}
+
+// ResultFunc adapts a function to a Handler that only receives site results.
+type ResultFunc func(result Result)
+
+// StageStarted does nothing.
+func (f ResultFunc) StageStarted(stage string) {}
+
+// SiteResult calls f with the result.
+func (f ResultFunc) SiteResult(result Result) {
+	f(result)
+}
+
+// StageFinished does nothing.
+func (f ResultFunc) StageFinished(stage string, report *Report, err error) {}
+
+// RunFinished does nothing.
+func (f ResultFunc) RunFinished(report *Report) {}
@@ -0,0 +1,79 @@
+package gosearch
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// UpstreamCatalog is the name of the catalog at DataURL.
+const UpstreamCatalog = "upstream"
+
+// LoadCatalog loads a catalog in data.json format from a file path or an http or https URL, fetching URLs with client
+// and verifying their signature according to trust. Local files are trusted as they are.
+// Its websites are attributed to the catalog's name field, or to the file name without its extension if it has none.
+func LoadCatalog(client *http.Client, source string, trust CatalogTrust) (Data, error) {
+	var data Data
+	var name string
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		fetched, err := fetchCatalog(client, source, trust)
+		if err != nil {
+			return Data{}, err
+		}
+		data, name = fetched, path.Base(strings.SplitN(source, "?", 2)[0])
+	} else {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			return Data{}, fmt.Errorf("error reading catalog: %w", err)
+		}
+		if data, err = ParseData(content); err != nil {
+			return Data{}, fmt.Errorf("error parsing %s: %w", source, err)
+		}
+		name = filepath.Base(source)
+	}
+	return data.named(strings.TrimSuffix(name, path.Ext(name))), nil
+}
+
+// named returns the catalog with its name defaulting to name and every website attributed to it.
+func (d Data) named(name string) Data {
+	if d.Name == "" {
+		d.Name = name
+	}
+	websites := make([]Website, len(d.Websites))
+	for i, website := range d.Websites {
+		website.Catalog = d.Name
+		websites[i] = website
+	}
+	d.Websites = websites
+	return d
+}
+
+// MergeData merges catalogs in order, matching websites by name case-insensitively. A website in a later catalog
+// replaces the whole entry of the same name from earlier catalogs, keeping its position, and a disabled entry
+// removes it. Websites that only appear in later catalogs are added after the earlier catalogs' websites.
+func MergeData(catalogs ...Data) Data {
+	var websites []Website
+	index := map[string]int{}
+	for _, catalog := range catalogs {
+		for _, website := range catalog.Websites {
+			key := strings.ToLower(website.Name)
+			if i, ok := index[key]; ok {
+				websites[i] = website
+				continue
+			}
+			index[key] = len(websites)
+			websites = append(websites, website)
+		}
+	}
+
+	merged := Data{Websites: []Website{}}
+	for _, website := range websites {
+		if !website.Disabled {
+			merged.Websites = append(merged.Websites, website)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,84 @@
+package gosearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeData(t *testing.T) {
+	upstream := Data{Name: UpstreamCatalog, Websites: []Website{
+		{Name: "GitHub", BaseURL: "https://github.com/{}"},
+		{Name: "Forum", BaseURL: "https://forum.example/u/{}"},
+		{Name: "Gitlab", BaseURL: "https://gitlab.com/{}"},
+	}}.named(UpstreamCatalog)
+	overlay := Data{Name: "internal", Websites: []Website{
+		{Name: "forum", BaseURL: "https://forum.example/members/{}"},
+		{Name: "Gitlab", Disabled: true},
+		{Name: "Intranet", BaseURL: "https://intranet.example/people/{}"},
+	}}.named("internal")
+
+	merged := MergeData(upstream, overlay)
+	var got [][2]string
+	for _, website := range merged.Websites {
+		got = append(got, [2]string{website.BaseURL, website.Catalog})
+	}
+	want := [][2]string{
+		{"https://github.com/{}", UpstreamCatalog},
+		{"https://forum.example/members/{}", "internal"},
+		{"https://intranet.example/people/{}", "internal"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// A later catalog can bring a disabled website back
+	restore := Data{Websites: []Website{{Name: "Gitlab", BaseURL: "https://gitlab.example/{}"}}}.named("restore")
+	if merged := MergeData(upstream, overlay, restore); merged.Index("Gitlab") < 0 {
+		t.Error("Gitlab was not restored by a later catalog")
+	}
+}
+
+func TestLoadCatalog(t *testing.T) {
+	dir := t.TempDir()
+	unnamed := filepath.Join(dir, "regional.json")
+	named := filepath.Join(dir, "overlay.json")
+	os.WriteFile(unnamed, []byte(`{"websites": [{"name": "A", "base_url": "https://a.example/{}", "errorType": "status_code"}]}`), 0o644)
+	os.WriteFile(named, []byte(`{"name": "internal", "websites": [{"name": "B", "base_url": "https://b.example/{}", "errorType": "status_code"}]}`), 0o644)
+
+	server := httptest.NewServer(http.FileServer(http.Dir(dir)))
+	t.Cleanup(server.Close)
+
+	tests := map[string]string{
+		unnamed:                           "regional",
+		named:                             "internal",
+		server.URL + "/regional.json?v=2": "regional",
+		server.URL + "/overlay.json":      "internal",
+	}
+	for source, want := range tests {
+		data, err := LoadCatalog(server.Client(), source, CatalogTrust{AllowUnsigned: true})
+		if err != nil {
+			t.Fatalf("%s: %v", source, err)
+		}
+		if data.Name != want || data.Websites[0].Catalog != want {
+			t.Errorf("%s: got catalog %q and website catalog %q, want %q", source, data.Name, data.Websites[0].Catalog, want)
+		}
+	}
+
+	if _, err := LoadCatalog(server.Client(), server.URL+"/missing.json", CatalogTrust{AllowUnsigned: true}); err == nil {
+		t.Error("missing remote catalog: expected an error")
+	}
+}
+
+func TestResultCatalog(t *testing.T) {
+	server := newFixtureServer(t)
+	website := Website{Name: "status", BaseURL: server.URL + "/status/{}", ErrorType: "status_code", Catalog: "internal"}
+	result := newTestSearcher(Data{}, Options{}).SearchWebsite(context.Background(), website, existingUser)
+	if result.Catalog != "internal" {
+		t.Errorf("got catalog %q, want internal", result.Catalog)
+	}
+}
@@ -0,0 +1,94 @@
+package gosearch
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParkingSignatures recognise domains that are parked or for sale rather than in use.
+type ParkingSignatures struct {
+	Nameservers  []string // Domains of parking services' name servers
+	Marketplaces []string // Domains of domain marketplaces that parked domains redirect to
+	Markers      []string // Lowercase text found on parking and for-sale pages
+}
+
+// DefaultParkingSignatures are the signatures of common parking services and domain marketplaces.
+var DefaultParkingSignatures = ParkingSignatures{
+	Nameservers: []string{
+		"above.com",
+		"afternic.com",
+		"bodis.com",
+		"dan.com",
+		"dnsowl.com",
+		"fabulous.com",
+		"hugedomains.com",
+		"namebrightdns.com",
+		"parkingcrew.net",
+		"parklogic.com",
+		"sedoparking.com",
+		"smartname.com",
+		"uniregistrymarket.link",
+		"ztomy.com",
+	},
+	Marketplaces: []string{
+		"afternic.com",
+		"atom.com",
+		"brandbucket.com",
+		"buydomains.com",
+		"dan.com",
+		"domainmarket.com",
+		"efty.com",
+		"hugedomains.com",
+		"sedo.com",
+		"squadhelp.com",
+		"undeveloped.com",
+		"uniregistry.com",
+	},
+	Markers: []string{
+		"this domain is for sale",
+		"this domain may be for sale",
+		"this domain name is for sale",
+		"buy this domain",
+		"make an offer on this domain",
+		"domain is parked",
+		"parked free, courtesy of",
+		"sedoparking.com",
+		"parkingcrew.net",
+		"window.park",
+	},
+}
+
+// match returns why the domain looks parked, or an empty string. It checks the domain's name servers, the host
+// its website finally answered from and the text of that page.
+func (p ParkingSignatures) match(result DomainResult, body []byte) string {
+	for _, ns := range result.NS {
+		if matchDomain(ns, p.Nameservers) != "" {
+			return fmt.Sprintf("parking name server %s", ns)
+		}
+	}
+
+	if final, err := url.Parse(result.FinalURL); err == nil && result.FinalURL != "" {
+		if marketplace := matchDomain(final.Hostname(), p.Marketplaces); marketplace != "" {
+			return fmt.Sprintf("redirects to marketplace %s", marketplace)
+		}
+	}
+
+	page := strings.ToLower(string(body))
+	for _, marker := range p.Markers {
+		if strings.Contains(page, marker) {
+			return fmt.Sprintf("page contains %q", marker)
+		}
+	}
+	return ""
+}
+
+// matchDomain returns the domain in domains that host is or is a subdomain of, or an empty string.
+func matchDomain(host string, domains []string) string {
+	for _, domain := range domains {
+		if sameDomain(host, domain) {
+			return domain
+		}
+	}
+	return ""
+}
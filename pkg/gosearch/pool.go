@@ -0,0 +1,194 @@
+package gosearch
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Proxy rotation strategies supported by ProxyPool.
+const (
+	RotateRoundRobin = "round-robin" // Use each proxy in turn
+	RotateRandom     = "random"      // Pick a random proxy for every request
+)
+
+// DefaultProxyCooldown is how long a failing proxy is left out of rotation by default.
+const DefaultProxyCooldown = time.Minute
+
+// ProxyStats holds the usage statistics of a single proxy in a ProxyPool.
+type ProxyStats struct {
+	Proxy    string `json:"proxy"`    // Proxy URL with any password redacted
+	Requests int    `json:"requests"` // Requests sent through the proxy
+	Failures int    `json:"failures"` // Requests that failed with a network error
+	Blocked  int    `json:"blocked"`  // Requests answered with 403 or 429
+	Healthy  bool   `json:"healthy"`  // Whether the proxy is currently in rotation
+}
+
+// ProxyPool rotates requests across a list of proxies, leaving proxies that fail or get blocked out of rotation for a cooldown.
+// A ProxyPool is safe for concurrent use and may be shared between Searchers.
+type ProxyPool struct {
+	strategy string
+	cooldown time.Duration
+
+	mu      sync.Mutex
+	proxies []*poolProxy
+	next    int
+}
+
+// poolProxy tracks the health of a single proxy in a pool.
+type poolProxy struct {
+	url            *url.URL
+	stats          ProxyStats
+	unhealthyUntil time.Time
+}
+
+// NewProxyPool creates a pool rotating across proxies with the given strategy and cooldown.
+func NewProxyPool(proxies []*url.URL, strategy string, cooldown time.Duration) (*ProxyPool, error) {
+	if len(proxies) == 0 {
+		return nil, errors.New("proxy pool is empty")
+	}
+	switch strategy {
+	case RotateRoundRobin, RotateRandom:
+	default:
+		return nil, fmt.Errorf("unknown proxy rotation %q (expected round-robin or random)", strategy)
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultProxyCooldown
+	}
+
+	pool := &ProxyPool{strategy: strategy, cooldown: cooldown}
+	for _, proxy := range proxies {
+		pool.proxies = append(pool.proxies, &poolProxy{
+			url:   normalizeProxy(proxy),
+			stats: ProxyStats{Proxy: proxy.Redacted()},
+		})
+	}
+	return pool, nil
+}
+
+// ReadProxyList reads proxy URLs, one per line. Blank lines and lines starting with # are ignored.
+func ReadProxyList(r io.Reader) ([]*url.URL, error) {
+	var proxies []*url.URL
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		proxy, err := ParseProxy(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		proxies = append(proxies, proxy)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading proxy list: %w", err)
+	}
+	return proxies, nil
+}
+
+// pick selects the proxy for the next request.
+// If every proxy is cooling down, the one that recovers soonest is used rather than failing the request.
+func (p *ProxyPool) pick() *poolProxy {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var healthy []*poolProxy
+	for _, proxy := range p.proxies {
+		if !now.Before(proxy.unhealthyUntil) {
+			healthy = append(healthy, proxy)
+		}
+	}
+
+	var chosen *poolProxy
+	switch {
+	case len(healthy) == 0:
+		chosen = p.proxies[0]
+		for _, proxy := range p.proxies[1:] {
+			if proxy.unhealthyUntil.Before(chosen.unhealthyUntil) {
+				chosen = proxy
+			}
+		}
+	case p.strategy == RotateRandom:
+		chosen = healthy[rand.IntN(len(healthy))]
+	default:
+		chosen = healthy[p.next%len(healthy)]
+		p.next++
+	}
+
+	chosen.stats.Requests++
+	return chosen
+}
+
+// record updates a proxy's health with the outcome of a request sent through it.
+func (p *ProxyPool) record(proxy *poolProxy, res *http.Response, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case err != nil:
+		// Requests cancelled by the caller say nothing about the proxy
+		if errors.Is(err, context.Canceled) {
+			return
+		}
+		proxy.stats.Failures++
+	case res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusTooManyRequests:
+		proxy.stats.Blocked++
+	default:
+		return
+	}
+	proxy.unhealthyUntil = time.Now().Add(p.cooldown)
+}
+
+// Stats returns the usage statistics of every proxy in the pool.
+func (p *ProxyPool) Stats() []ProxyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	stats := make([]ProxyStats, len(p.proxies))
+	for i, proxy := range p.proxies {
+		stats[i] = proxy.stats
+		stats[i].Healthy = !now.Before(proxy.unhealthyUntil)
+	}
+	return stats
+}
+
+// Transport returns a round tripper that sends each request through base using the next proxy in the pool.
+// base's own proxy setting is replaced; its TLS and connection settings are kept.
+func (p *ProxyPool) Transport(base *http.Transport) http.RoundTripper {
+	transport := base.Clone()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		if proxy, ok := req.Context().Value(poolProxyKey{}).(*poolProxy); ok {
+			return proxy.url, nil
+		}
+		return nil, nil
+	}
+	return &poolTransport{pool: p, transport: transport}
+}
+
+// poolProxyKey is the context key holding the proxy chosen for a request.
+type poolProxyKey struct{}
+
+// poolTransport sends requests through a rotating proxy pool.
+type poolTransport struct {
+	pool      *ProxyPool
+	transport *http.Transport
+}
+
+// RoundTrip sends the request through the next proxy and records the outcome.
+func (t *poolTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	proxy := t.pool.pick()
+	res, err := t.transport.RoundTrip(req.WithContext(context.WithValue(req.Context(), poolProxyKey{}, proxy)))
+	t.pool.record(proxy, res, err)
+	return res, err
+}
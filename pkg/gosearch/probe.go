@@ -0,0 +1,116 @@
+package gosearch
+
+import (
+	"bytes"
+	"context"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ProbeResponse is a website's response to a probe, as GoSearch sees it when searching.
+type ProbeResponse struct {
+	URL        string      `json:"url"`         // Requested URL
+	FinalURL   string      `json:"final_url"`   // URL of the response, after any redirects that were followed
+	Status     string      `json:"status"`      // Status line, e.g. "200 OK"
+	StatusCode int         `json:"status_code"` // Status code
+	Header     http.Header `json:"header"`      // Response headers
+	Body       []byte      `json:"-"`           // Decompressed response body
+}
+
+// Title returns the contents of the body's <title> tag, or an empty string if it has none.
+func (r ProbeResponse) Title() string {
+	return PageTitle(r.Body)
+}
+
+// titlePattern matches an HTML <title> tag.
+var titlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// PageTitle returns the contents of an HTML page's <title> tag, or an empty string if it has none.
+func PageTitle(body []byte) string {
+	match := titlePattern.FindSubmatch(body)
+	if match == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(html.UnescapeString(string(match[1]))), " ")
+}
+
+// Probe requests the website for the username exactly as SearchWebsite would, including its session, cookies,
+// secrets and redirect settings, and returns the response instead of a result.
+// If compressed is false, an uncompressed body is requested instead of a gzip, deflate or br one.
+// It is used to work out how a website answers for existing and missing profiles.
+func (s *Searcher) Probe(ctx context.Context, website Website, username string, compressed bool) (ProbeResponse, error) {
+	ctx, url, err := s.probeURL(ctx, website, username)
+	if err != nil {
+		return ProbeResponse{}, err
+	}
+
+	// Create request
+	req, err := s.newRequest(ctx, website, url)
+	if err != nil {
+		return ProbeResponse{}, err
+	}
+	if !compressed {
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+
+	// Send request
+	client, err := s.client(ctx, website)
+	if err != nil {
+		return ProbeResponse{}, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return ProbeResponse{}, err
+	}
+	defer res.Body.Close()
+
+	// Read response body
+	body, err := ReadBody(res)
+	if err != nil {
+		return ProbeResponse{}, err
+	}
+
+	return ProbeResponse{
+		URL:        url,
+		FinalURL:   res.Request.URL.String(),
+		Status:     res.Status,
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       body,
+	}, nil
+}
+
+// DiffLines compares two bodies line by line, ignoring surrounding whitespace and blank lines.
+// It returns the distinct lines only found in a and those only found in b, in the order they appear.
+func DiffLines(a, b []byte) (onlyA, onlyB []string) {
+	linesA, linesB := lineSet(a), lineSet(b)
+	return missingLines(a, linesB), missingLines(b, linesA)
+}
+
+// lineSet returns the set of trimmed, non-blank lines in body.
+func lineSet(body []byte) map[string]bool {
+	set := map[string]bool{}
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			set[string(line)] = true
+		}
+	}
+	return set
+}
+
+// missingLines returns the distinct trimmed, non-blank lines of body that are not in set.
+func missingLines(body []byte, set map[string]bool) []string {
+	var missing []string
+	seen := map[string]bool{}
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		text := string(bytes.TrimSpace(line))
+		if text == "" || set[text] || seen[text] {
+			continue
+		}
+		seen[text] = true
+		missing = append(missing, text)
+	}
+	return missing
+}
@@ -0,0 +1,75 @@
+package gosearch
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestProbe(t *testing.T) {
+	server := newFixtureServer(t)
+	searcher := newTestSearcher(Data{}, Options{})
+
+	website := Website{Name: "moved", BaseURL: server.URL + "/moved/{}"}
+	response, err := searcher.Probe(context.Background(), website, missingUser, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != 302 || response.FinalURL != server.URL+"/moved/"+missingUser {
+		t.Errorf("without redirects: got %d at %s", response.StatusCode, response.FinalURL)
+	}
+
+	website.FollowRedirects = true
+	response, err = searcher.Probe(context.Background(), website, missingUser, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.StatusCode != 200 || response.FinalURL != server.URL+"/notfound" {
+		t.Errorf("following redirects: got %d at %s", response.StatusCode, response.FinalURL)
+	}
+}
+
+func TestProbeCompressed(t *testing.T) {
+	server := newFixtureServer(t)
+	searcher := newTestSearcher(Data{}, Options{})
+
+	for _, encoding := range []string{"gzip", "deflate", "br"} {
+		website := Website{Name: encoding, BaseURL: server.URL + "/compressed/" + encoding + "/{}"}
+		response, err := searcher.Probe(context.Background(), website, existingUser, true)
+		if err != nil {
+			t.Fatalf("%s: %v", encoding, err)
+		}
+		if string(response.Body) != profilePage(existingUser) {
+			t.Errorf("%s: got body %q", encoding, response.Body)
+		}
+		if response.Header.Get("Content-Encoding") != encoding {
+			t.Errorf("%s: got Content-Encoding %q", encoding, response.Header.Get("Content-Encoding"))
+		}
+	}
+}
+
+func TestPageTitle(t *testing.T) {
+	tests := map[string]string{
+		"<html><head><title>alice | Example</title></head></html>": "alice | Example",
+		"<TITLE lang=\"en\">\n  Tom &amp; Jerry\n</TITLE>":         "Tom & Jerry",
+		"<html>no title</html>":                                    "",
+	}
+	for body, want := range tests {
+		if got := PageTitle([]byte(body)); got != want {
+			t.Errorf("PageTitle(%q) = %q, want %q", body, got, want)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	a := []byte("<html>\n  <h1>alice</h1>\n<p>bio</p>\n<p>bio</p>\n</html>")
+	b := []byte("<html>\n<h1>Not found</h1>\n\n</html>\n")
+
+	onlyA, onlyB := DiffLines(a, b)
+	if want := []string{"<h1>alice</h1>", "<p>bio</p>"}; !reflect.DeepEqual(onlyA, want) {
+		t.Errorf("only in a: got %q, want %q", onlyA, want)
+	}
+	if want := []string{"<h1>Not found</h1>"}; !reflect.DeepEqual(onlyB, want) {
+		t.Errorf("only in b: got %q, want %q", onlyB, want)
+	}
+}
@@ -0,0 +1,136 @@
+package gosearch
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+)
+
+// Names of the built-in header profiles.
+const (
+	ProfileFirefoxWindows = "firefox-windows" // Firefox on Windows (default)
+	ProfileFirefoxLinux   = "firefox-linux"   // Firefox on Linux
+	ProfileChromeDesktop  = "chrome-desktop"  // Chrome on Windows
+	ProfileSafariIOS      = "safari-ios"      // Safari on iPhone
+	ProfileCurl           = "curl"            // curl command-line client
+	ProfileRotate         = "rotate"          // A random browser profile for every request
+)
+
+// DefaultHeaderProfile is the header profile used when neither the options nor the website choose one.
+const DefaultHeaderProfile = ProfileFirefoxWindows
+
+// HeaderProfile is a consistent set of request headers identifying a single client.
+type HeaderProfile struct {
+	Name    string      // Profile name
+	Headers http.Header // Headers sent with every request, including User-Agent
+}
+
+// firefoxHeaders returns the headers Firefox sends when navigating to a page.
+func firefoxHeaders(userAgent string) http.Header {
+	return http.Header{
+		"User-Agent":                {userAgent},
+		"Accept":                    {"text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8"},
+		"Accept-Language":           {"en-US,en;q=0.5"},
+		"Accept-Encoding":           {"gzip, deflate, br"},
+		"Connection":                {"keep-alive"},
+		"Upgrade-Insecure-Requests": {"1"},
+		"Sec-Fetch-Dest":            {"document"},
+		"Sec-Fetch-Mode":            {"navigate"},
+		"Sec-Fetch-Site":            {"none"},
+		"Sec-Fetch-User":            {"?1"},
+		"Cache-Control":             {"max-age=0"},
+	}
+}
+
+// HeaderProfiles holds the built-in header profiles keyed by name.
+var HeaderProfiles = map[string]HeaderProfile{
+	ProfileFirefoxWindows: {
+		Name:    ProfileFirefoxWindows,
+		Headers: firefoxHeaders(DefaultUserAgent),
+	},
+	ProfileFirefoxLinux: {
+		Name:    ProfileFirefoxLinux,
+		Headers: firefoxHeaders("Mozilla/5.0 (X11; Linux x86_64; rv:140.0) Gecko/20100101 Firefox/140.0"),
+	},
+	ProfileChromeDesktop: {
+		Name: ProfileChromeDesktop,
+		Headers: http.Header{
+			"User-Agent":                {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/138.0.0.0 Safari/537.36"},
+			"Sec-Ch-Ua":                 {`"Not)A;Brand";v="8", "Chromium";v="138", "Google Chrome";v="138"`},
+			"Sec-Ch-Ua-Mobile":          {"?0"},
+			"Sec-Ch-Ua-Platform":        {`"Windows"`},
+			"Upgrade-Insecure-Requests": {"1"},
+			"Accept":                    {"text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7"},
+			"Sec-Fetch-Site":            {"none"},
+			"Sec-Fetch-Mode":            {"navigate"},
+			"Sec-Fetch-User":            {"?1"},
+			"Sec-Fetch-Dest":            {"document"},
+			"Accept-Encoding":           {"gzip, deflate, br"},
+			"Accept-Language":           {"en-US,en;q=0.9"},
+		},
+	},
+	ProfileSafariIOS: {
+		Name: ProfileSafariIOS,
+		Headers: http.Header{
+			"User-Agent":      {"Mozilla/5.0 (iPhone; CPU iPhone OS 18_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/18.5 Mobile/15E148 Safari/604.1"},
+			"Accept":          {"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"},
+			"Sec-Fetch-Site":  {"none"},
+			"Sec-Fetch-Mode":  {"navigate"},
+			"Sec-Fetch-Dest":  {"document"},
+			"Accept-Language": {"en-US,en;q=0.9"},
+			"Accept-Encoding": {"gzip, deflate, br"},
+		},
+	},
+	ProfileCurl: {
+		Name: ProfileCurl,
+		Headers: http.Header{
+			"User-Agent": {"curl/8.7.1"},
+			"Accept":     {"*/*"},
+		},
+	},
+}
+
+// rotatingProfiles lists the browser profiles chosen from when rotating.
+var rotatingProfiles = []string{ProfileFirefoxWindows, ProfileFirefoxLinux, ProfileChromeDesktop, ProfileSafariIOS}
+
+// HeaderProfileNames returns the names accepted as a header profile, sorted.
+func HeaderProfileNames() []string {
+	names := []string{ProfileRotate}
+	for name := range HeaderProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupHeaderProfile returns the named header profile. ProfileRotate picks a random browser profile on every call.
+func LookupHeaderProfile(name string) (HeaderProfile, error) {
+	if name == ProfileRotate {
+		name = rotatingProfiles[rand.IntN(len(rotatingProfiles))]
+	}
+	profile, ok := HeaderProfiles[name]
+	if !ok {
+		return HeaderProfile{}, fmt.Errorf("unknown header profile %q (expected one of %v)", name, HeaderProfileNames())
+	}
+	return profile, nil
+}
+
+// Apply sets the profile's headers on the request, replacing the User-Agent if userAgent is not empty.
+func (p HeaderProfile) Apply(req *http.Request, userAgent string) {
+	for key, values := range p.Headers {
+		req.Header[key] = values
+	}
+	if userAgent != "" {
+		req.Header.Set("User-Agent", userAgent)
+	}
+}
+
+// headerProfile returns the header profile for a request to the website: its pinned profile, or the Searcher's.
+func (s *Searcher) headerProfile(website Website) (HeaderProfile, error) {
+	name := website.HeaderProfile
+	if name == "" {
+		name = s.options.HeaderProfile
+	}
+	return LookupHeaderProfile(name)
+}
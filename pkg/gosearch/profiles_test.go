@@ -0,0 +1,128 @@
+package gosearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLookupHeaderProfile(t *testing.T) {
+	for name := range HeaderProfiles {
+		profile, err := LookupHeaderProfile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Name != name || profile.Headers.Get("User-Agent") == "" || profile.Headers.Get("Accept") == "" {
+			t.Errorf("%s: got profile %q with headers %v", name, profile.Name, profile.Headers)
+		}
+	}
+
+	// Rotation picks a browser profile; curl would give the search away
+	for range 50 {
+		profile, err := LookupHeaderProfile(ProfileRotate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Contains(rotatingProfiles, profile.Name) {
+			t.Fatalf("rotation picked %q", profile.Name)
+		}
+	}
+
+	if _, err := LookupHeaderProfile("netscape"); err == nil || !strings.Contains(err.Error(), ProfileCurl) {
+		t.Errorf("got error %v, want the unknown profile reported with the known ones", err)
+	}
+	if names := HeaderProfileNames(); !slices.IsSorted(names) || !slices.Contains(names, ProfileRotate) {
+		t.Errorf("HeaderProfileNames = %q", names)
+	}
+}
+
+func TestBrowserProfilesAreConsistent(t *testing.T) {
+	// Browsers send Sec-Fetch headers when navigating, and compress responses GoSearch can decode
+	for _, name := range rotatingProfiles {
+		headers := HeaderProfiles[name].Headers
+		for _, key := range []string{"Accept-Language", "Accept-Encoding", "Sec-Fetch-Mode", "Sec-Fetch-Dest", "Sec-Fetch-Site"} {
+			if headers.Get(key) == "" {
+				t.Errorf("%s has no %s header", name, key)
+			}
+		}
+	}
+	if ua := HeaderProfiles[ProfileChromeDesktop].Headers.Get("User-Agent"); !strings.Contains(ua, "Chrome/") {
+		t.Errorf("chrome-desktop sends User-Agent %q", ua)
+	}
+	if ua := HeaderProfiles[ProfileSafariIOS].Headers.Get("User-Agent"); !strings.Contains(ua, "iPhone") {
+		t.Errorf("safari-ios sends User-Agent %q", ua)
+	}
+	if ua := HeaderProfiles[ProfileFirefoxLinux].Headers.Get("User-Agent"); !strings.Contains(ua, "Linux") {
+		t.Errorf("firefox-linux sends User-Agent %q", ua)
+	}
+}
+
+func TestHeaderProfileSelection(t *testing.T) {
+	headers := make(chan http.Header, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers <- r.Header.Clone()
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name    string
+		options Options
+		website Website
+		profile string // Profile whose headers are expected
+		agent   string // Expected User-Agent, if not the profile's
+	}{
+		{"default", Options{}, Website{}, DefaultHeaderProfile, ""},
+		{"searcher profile", Options{HeaderProfile: ProfileCurl}, Website{}, ProfileCurl, ""},
+		{"pinned by website", Options{HeaderProfile: ProfileCurl}, Website{HeaderProfile: ProfileSafariIOS}, ProfileSafariIOS, ""},
+		{"searcher user agent", Options{HeaderProfile: ProfileChromeDesktop, UserAgent: "Agent/1"}, Website{}, ProfileChromeDesktop, "Agent/1"},
+		{"website user agent", Options{UserAgent: "Agent/1"}, Website{HeaderProfile: ProfileChromeDesktop, UserAgent: "Agent/2"}, ProfileChromeDesktop, "Agent/2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			website := tt.website
+			website.Name, website.BaseURL, website.ErrorType = "Headers", server.URL+"/{}", "status_code"
+			newTestSearcher(Data{}, tt.options).SearchWebsite(context.Background(), website, existingUser)
+			got := <-headers
+
+			want := HeaderProfiles[tt.profile].Headers
+			for key := range want {
+				if key == "User-Agent" || key == "Connection" {
+					continue
+				}
+				if got.Get(key) != want.Get(key) {
+					t.Errorf("%s = %q, want %q from %s", key, got.Get(key), want.Get(key), tt.profile)
+				}
+			}
+			agent := tt.agent
+			if agent == "" {
+				agent = want.Get("User-Agent")
+			}
+			if got.Get("User-Agent") != agent {
+				t.Errorf("User-Agent = %q, want %q", got.Get("User-Agent"), agent)
+			}
+		})
+	}
+
+	// Rotation sends one consistent browser profile per request
+	searcher := newTestSearcher(Data{}, Options{HeaderProfile: ProfileRotate})
+	website := Website{Name: "Headers", BaseURL: server.URL + "/{}", ErrorType: "status_code"}
+	seen := map[string]bool{}
+	for range 40 {
+		searcher.SearchWebsite(context.Background(), website, existingUser)
+		got := <-headers
+		for _, name := range rotatingProfiles {
+			if profile := HeaderProfiles[name].Headers; got.Get("User-Agent") == profile.Get("User-Agent") {
+				seen[name] = true
+				if got.Get("Accept") != profile.Get("Accept") {
+					t.Errorf("%s User-Agent sent with another profile's Accept %q", name, got.Get("Accept"))
+				}
+			}
+		}
+	}
+	if len(seen) < 2 {
+		t.Errorf("rotation only used %v", seen)
+	}
+}
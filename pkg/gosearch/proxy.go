@@ -0,0 +1,47 @@
+package gosearch
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ParseProxy parses a proxy URL such as http://127.0.0.1:8080 or socks5h://127.0.0.1:9050.
+// Supported schemes are http, https, socks5 and socks5h.
+func ParseProxy(raw string) (*url.URL, error) {
+	proxy, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", raw, err)
+	}
+
+	switch strings.ToLower(proxy.Scheme) {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("invalid proxy %q: unsupported scheme %q (expected http, https, socks5 or socks5h)", raw, proxy.Scheme)
+	}
+	if proxy.Host == "" {
+		return nil, fmt.Errorf("invalid proxy %q: missing host", raw)
+	}
+
+	return proxy, nil
+}
+
+// proxyFunc returns the transport proxy function for the proxy, falling back to the environment when proxy is nil.
+func proxyFunc(proxy *url.URL) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return http.ProxyFromEnvironment
+	}
+	return http.ProxyURL(normalizeProxy(proxy))
+}
+
+// normalizeProxy converts a proxy URL into the form accepted by http.Transport.
+func normalizeProxy(proxy *url.URL) *url.URL {
+	// Go's SOCKS5 dialer always resolves hostnames through the proxy, so socks5 already behaves like socks5h
+	if strings.EqualFold(proxy.Scheme, "socks5h") {
+		normalized := *proxy
+		normalized.Scheme = "socks5"
+		return &normalized
+	}
+	return proxy
+}
@@ -0,0 +1,184 @@
+package gosearch
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// recordIndex is the file in a recording directory listing every recorded request in order.
+const recordIndex = "index.jsonl"
+
+// RecordEntry describes one request in a recording.
+// Request headers are never recorded, so recordings do not contain cookies, tokens or API keys sent by GoSearch.
+type RecordEntry struct {
+	Time   time.Time `json:"time"`             // When the response was received
+	Method string    `json:"method"`           // Request method
+	URL    string    `json:"url"`              // Request URL
+	Status int       `json:"status,omitempty"` // Response status code
+	File   string    `json:"file,omitempty"`   // File in the recording directory holding the raw response
+	Error  string    `json:"error,omitempty"`  // Error returned instead of a response
+}
+
+// Recorder saves every response GoSearch receives to a directory so a run can be replayed offline with a Replayer.
+// Each response is stored raw in its own file, and index.jsonl lists the requests in the order they were made.
+// Responses to requests carrying secrets, such as site secrets or the Breach Directory API key, are not stored;
+// the index records them as errors instead.
+// A Recorder is safe for concurrent use.
+type Recorder struct {
+	dir string
+
+	mu    sync.Mutex
+	index *os.File
+}
+
+// NewRecorder creates a recorder saving responses in dir, creating dir if needed.
+// Recording into an existing recording adds to it, replacing responses to repeated requests.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("error creating recording directory: %w", err)
+	}
+	index, err := os.OpenFile(filepath.Join(dir, recordIndex), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("error creating recording index: %w", err)
+	}
+	return &Recorder{dir: dir, index: index}, nil
+}
+
+// Close closes the recording's index.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.index.Close()
+}
+
+// add appends an entry to the recording's index.
+func (r *Recorder) add(entry RecordEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, err = r.index.Write(append(line, '\n'))
+	return err
+}
+
+// Transport returns a round tripper that sends requests through next and records their responses.
+func (r *Recorder) Transport(next http.RoundTripper) http.RoundTripper {
+	return &recordTransport{recorder: r, next: next}
+}
+
+// recordTransport records the responses to requests sent through it.
+type recordTransport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+// RoundTrip sends the request and records the response or error.
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.next.RoundTrip(req)
+	entry := RecordEntry{
+		Time:   time.Now(),
+		Method: req.Method,
+		URL:    req.URL.String(),
+	}
+
+	// A response that cannot be recorded is still returned; replaying the run fails the request instead
+	switch {
+	case err != nil:
+		entry.Error = err.Error()
+	case req.Context().Value(secretKey{}) != nil:
+		entry.Status = res.StatusCode
+		entry.Error = "response not recorded: request carried secrets"
+	default:
+		if err := bufferBody(res); err != nil {
+			return nil, err
+		}
+		entry.Status = res.StatusCode
+		entry.File = responseFile(req)
+		if err := writeResponse(filepath.Join(t.recorder.dir, entry.File), res); err != nil {
+			entry.File = ""
+			entry.Error = "response not recorded: " + err.Error()
+		}
+	}
+
+	t.recorder.add(entry)
+	return res, err
+}
+
+// Replayer serves the responses saved by a Recorder without network access.
+// Requests are matched by method and URL; the last recorded response to a request is served.
+// A Replayer is safe for concurrent use.
+type Replayer struct {
+	dir     string
+	entries map[string]RecordEntry
+}
+
+// NewReplayer loads the recording in dir.
+func NewReplayer(dir string) (*Replayer, error) {
+	file, err := os.Open(filepath.Join(dir, recordIndex))
+	if err != nil {
+		return nil, fmt.Errorf("error opening recording: %w", err)
+	}
+	defer file.Close()
+
+	entries := map[string]RecordEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		var entry RecordEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("error parsing recording index line %d: %w", line, err)
+		}
+		entries[entry.Method+" "+entry.URL] = entry
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading recording index: %w", err)
+	}
+
+	return &Replayer{dir: dir, entries: entries}, nil
+}
+
+// Len returns how many distinct requests the recording holds.
+func (r *Replayer) Len() int {
+	return len(r.entries)
+}
+
+// Transport returns a round tripper that serves recorded responses and fails requests that were not recorded.
+func (r *Replayer) Transport() http.RoundTripper {
+	return replayTransport{replayer: r}
+}
+
+// replayTransport serves requests from a Replayer.
+type replayTransport struct {
+	replayer *Replayer
+}
+
+// RoundTrip returns the recorded response or error for the request.
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	entry, ok := t.replayer.entries[req.Method+" "+req.URL.String()]
+	switch {
+	case !ok:
+		return nil, fmt.Errorf("no recorded response for %s %s", req.Method, req.URL)
+	case entry.File == "":
+		return nil, errors.New(entry.Error)
+	}
+
+	res, err := readResponse(filepath.Join(t.replayer.dir, entry.File), req)
+	if err != nil {
+		return nil, fmt.Errorf("error reading recorded response for %s %s: %w", req.Method, req.URL, err)
+	}
+	return res, nil
+}
@@ -0,0 +1,110 @@
+package gosearch
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// searchStatuses searches every website for the username and returns each website's status.
+func searchStatuses(searcher *Searcher, username string) map[string]string {
+	statuses := map[string]string{}
+	for result := range searcher.Search(context.Background(), username) {
+		statuses[result.Website] = result.Status
+	}
+	return statuses
+}
+
+func TestRecordReplay(t *testing.T) {
+	server := httptest.NewServer(fixtureHandler())
+	data := Data{Websites: []Website{
+		{Name: "status", BaseURL: server.URL + "/status/{}", ErrorType: "status_code", ErrorCode: 404},
+		{Name: "message", BaseURL: server.URL + "/message/{}", ErrorType: "errorMsg", ErrorMsg: "User not found"},
+		{Name: "presence", BaseURL: server.URL + "/presence/{}", ErrorType: "profilePresence", ErrorMsg: "profile-header"},
+		{Name: "consent", BaseURL: server.URL + "/cookie/{}", ErrorType: "errorMsg", ErrorMsg: "User not found"},
+	}}
+	secrets := Secrets{"consent": {Host: "127.0.0.1", Cookies: []Cookie{{Name: "consent", Value: "yes"}}}}
+	endpoints := newBreachServer(t)
+
+	// Record a run against the live fixtures
+	dir := t.TempDir()
+	recorder, err := NewRecorder(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	searcher := newTestSearcher(data, Options{Secrets: secrets, Endpoints: endpoints, Record: recorder})
+	recorded := map[string]map[string]string{}
+	for _, username := range []string{existingUser, missingUser} {
+		recorded[username] = searchStatuses(searcher, username)
+	}
+	if recorded[existingUser]["consent"] != StatusFound {
+		t.Fatalf("got recorded statuses %v, want the consent profile found with its secret cookie", recorded[existingUser])
+	}
+	if _, err := searcher.SearchBreachDirectory(context.Background(), existingUser, testAPIKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := searcher.HudsonRock(context.Background(), existingUser); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := searcher.SearchProxyNova(context.Background(), existingUser); err != nil {
+		t.Fatal(err)
+	}
+	recorder.Close()
+	server.Close()
+
+	// Replay it without the fixtures; only the secret-bearing requests were left out
+	replayer, err := NewReplayer(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	searcher = newTestSearcher(data, Options{Secrets: secrets, Endpoints: endpoints, Replay: replayer})
+	for _, username := range []string{existingUser, missingUser} {
+		want := recorded[username]
+		want["consent"] = StatusError
+		if got := searchStatuses(searcher, username); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: replayed %v, want %v", username, got, want)
+		}
+	}
+	if _, err := searcher.SearchBreachDirectory(context.Background(), existingUser, testAPIKey); err == nil || !strings.Contains(err.Error(), "not recorded") {
+		t.Errorf("Breach Directory: got error %v, want the response not recorded", err)
+	}
+	if _, err := searcher.HudsonRock(context.Background(), existingUser); err == nil || !strings.Contains(err.Error(), "not recorded") {
+		t.Errorf("HudsonRock: got error %v, want the response not recorded", err)
+	}
+	if _, err := searcher.SearchProxyNova(context.Background(), existingUser); err == nil || !strings.Contains(err.Error(), "not recorded") {
+		t.Errorf("ProxyNova: got error %v, want the response not recorded", err)
+	}
+
+	// No breach endpoint has a recorded response
+	index, err := os.ReadFile(filepath.Join(dir, recordIndex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(index)), "\n") {
+		var entry RecordEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		for _, endpoint := range []string{endpoints.HudsonRock, endpoints.ProxyNova, endpoints.BreachDirectory, endpoints.Weakpass} {
+			if strings.HasPrefix(entry.URL, endpoint) && entry.File != "" {
+				t.Errorf("%s was recorded to %s", entry.URL, entry.File)
+			}
+		}
+	}
+
+	// Nothing in the recording holds the secrets or the responses they unlocked
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	for _, file := range files {
+		content, _ := os.ReadFile(file)
+		for _, secret := range []string{"consent=yes", testAPIKey, "hunter2", "letmein", "RedLine"} {
+			if strings.Contains(string(content), secret) {
+				t.Errorf("%s contains %q", filepath.Base(file), secret)
+			}
+		}
+	}
+}
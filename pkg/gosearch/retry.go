@@ -0,0 +1,142 @@
+package gosearch
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// Retry defaults used when Options leaves them unset.
+const (
+	DefaultRetryBackoff = 500 * time.Millisecond // Base delay before the first retry
+	DefaultRetryBudget  = 100                    // Maximum retries across a whole search
+)
+
+// Limits keeping a single request from waiting too long between retries.
+const (
+	maxRetryBackoff = 30 * time.Second // Longest computed backoff delay
+	maxRetryAfter   = time.Minute      // Longest Retry-After delay honoured; longer delays are not retried
+)
+
+// retryTransport retries requests that fail with a network error, 429 or 5xx using jittered exponential backoff.
+type retryTransport struct {
+	next    http.RoundTripper
+	retries int
+	backoff time.Duration
+	budget  *atomic.Int64 // Retries left, shared by every request of a Searcher
+}
+
+// RoundTrip sends the request, retrying transient failures while attempts and budget remain.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := t.next.RoundTrip(req)
+		if attempt >= t.retries || !retryable(req, res, err) {
+			return res, err
+		}
+
+		// Wait as long as the server asks, or back off exponentially with full jitter
+		delay := t.delay(attempt)
+		if res != nil {
+			if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+				if retryAfter > maxRetryAfter {
+					return res, err
+				}
+				delay = retryAfter
+			}
+		}
+
+		// Stop retrying once the budget is spent so one bad site cannot stall the search
+		if t.budget.Add(-1) < 0 {
+			return res, err
+		}
+
+		if res != nil {
+			io.Copy(io.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns a random backoff delay for the attempt, doubling the upper bound with each attempt.
+func (t *retryTransport) delay(attempt int) time.Duration {
+	limit := t.backoff << attempt
+	if limit <= 0 || limit > maxRetryBackoff {
+		limit = maxRetryBackoff
+	}
+	return rand.N(limit) + 1
+}
+
+// retryable reports whether a request's outcome is a transient failure worth retrying.
+func retryable(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		// Cancelled requests, missing hosts and invalid certificates will fail again
+		var dnsErr *net.DNSError
+		var certErr *tls.CertificateVerificationError
+		switch {
+		case req.Context().Err() != nil,
+			errors.As(err, &dnsErr) && dnsErr.IsNotFound,
+			errors.As(err, &certErr):
+			return false
+		}
+		return true
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// wrap wraps transport so its requests are retried according to the Searcher's options,
+// serving responses from the Searcher's cache first if it has one and recording them if recording.
+// When replaying, the recording replaces transport entirely so no request reaches the network.
+func (s *Searcher) wrap(transport http.RoundTripper) http.RoundTripper {
+	if s.options.Replay != nil {
+		return s.options.Replay.Transport()
+	}
+	if s.options.Retries > 0 {
+		transport = &retryTransport{
+			next:    transport,
+			retries: s.options.Retries,
+			backoff: s.options.RetryBackoff,
+			budget:  s.retryBudget,
+		}
+	}
+	if s.options.Cache != nil {
+		transport = s.options.Cache.Transport(transport)
+	}
+	if s.options.Record != nil {
+		transport = s.options.Record.Transport(transport)
+	}
+	return transport
+}
+
+// RetriesUsed returns how many retries the Searcher has made.
+func (s *Searcher) RetriesUsed() int {
+	used := int64(s.options.RetryBudget) - s.retryBudget.Load()
+	return int(min(used, int64(s.options.RetryBudget)))
+}
@@ -0,0 +1,180 @@
+package gosearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyServer answers with failures before answering 200, counting the attempts it received.
+type flakyServer struct {
+	*httptest.Server
+	attempts atomic.Int64
+}
+
+// newFlakyServer starts a server answering status, with the Retry-After header if set, for the first failures requests.
+// A negative failures fails every request.
+func newFlakyServer(t *testing.T, failures int, status int, retryAfter string) *flakyServer {
+	t.Helper()
+	s := &flakyServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempt := s.attempts.Add(1); failures < 0 || attempt <= int64(failures) {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte("<html>profile</html>"))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// search searches the server's status_code website with the searcher.
+func (s *flakyServer) search(searcher *Searcher) Result {
+	website := Website{Name: "Flaky", BaseURL: s.URL + "/{}", ErrorType: "status_code"}
+	return searcher.SearchWebsite(context.Background(), website, existingUser)
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusServiceUnavailable} {
+		t.Run(strconv.Itoa(status), func(t *testing.T) {
+			server := newFlakyServer(t, 2, status, "")
+			searcher := newTestSearcher(Data{}, Options{Retries: 3, RetryBackoff: time.Millisecond})
+			if result := server.search(searcher); result.Status != StatusFound {
+				t.Errorf("got status %s (%s), want found after retrying", result.Status, result.Error)
+			}
+			if got := server.attempts.Load(); got != 3 {
+				t.Errorf("got %d attempts, want 3", got)
+			}
+			if got := searcher.RetriesUsed(); got != 2 {
+				t.Errorf("RetriesUsed = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestRetryLimits(t *testing.T) {
+	// Without retries a failure is final
+	server := newFlakyServer(t, 1, http.StatusServiceUnavailable, "")
+	if result := server.search(newTestSearcher(Data{}, Options{})); result.Status == StatusFound || server.attempts.Load() != 1 {
+		t.Errorf("got status %s after %d attempts, want one failed attempt", result.Status, server.attempts.Load())
+	}
+
+	// Retries stop after the configured number
+	server = newFlakyServer(t, -1, http.StatusBadGateway, "")
+	result := server.search(newTestSearcher(Data{}, Options{Retries: 2, RetryBackoff: time.Millisecond}))
+	if result.Status == StatusFound || server.attempts.Load() != 3 {
+		t.Errorf("got status %s after %d attempts, want 3 failed attempts", result.Status, server.attempts.Load())
+	}
+
+	// Answers that will not change are not retried
+	server = newFlakyServer(t, -1, http.StatusNotFound, "")
+	server.search(newTestSearcher(Data{}, Options{Retries: 3, RetryBackoff: time.Millisecond}))
+	if got := server.attempts.Load(); got != 1 {
+		t.Errorf("got %d attempts for a 404, want 1", got)
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	// The budget is shared by every request of the Searcher, so once spent failures are no longer retried
+	server := newFlakyServer(t, -1, http.StatusServiceUnavailable, "")
+	searcher := newTestSearcher(Data{}, Options{Retries: 5, RetryBackoff: time.Millisecond, RetryBudget: 3})
+
+	server.search(searcher)
+	if got := server.attempts.Load(); got != 4 {
+		t.Errorf("got %d attempts, want the first try and 3 retries", got)
+	}
+	server.search(searcher)
+	if got := server.attempts.Load(); got != 5 {
+		t.Errorf("got %d attempts, want a single attempt once the budget is spent", got-4)
+	}
+	if got := searcher.RetriesUsed(); got != 3 {
+		t.Errorf("RetriesUsed = %d, want the whole budget", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	// The server's Retry-After is waited for instead of the backoff
+	server := newFlakyServer(t, 1, http.StatusTooManyRequests, "1")
+	searcher := newTestSearcher(Data{}, Options{Retries: 1, RetryBackoff: time.Millisecond})
+	start := time.Now()
+	if result := server.search(searcher); result.Status != StatusFound {
+		t.Errorf("got status %s (%s), want found after waiting", result.Status, result.Error)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %v, want the 1s Retry-After honoured", elapsed)
+	}
+
+	// Delays longer than maxRetryAfter are not waited for at all
+	server = newFlakyServer(t, 1, http.StatusTooManyRequests, strconv.Itoa(int(2*maxRetryAfter/time.Second)))
+	start = time.Now()
+	server.search(searcher)
+	if got := server.attempts.Load(); got != 1 || time.Since(start) > time.Second {
+		t.Errorf("got %d attempts after %v, want no retry", got, time.Since(start))
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	// Cancelling the search stops the wait between retries
+	server := newFlakyServer(t, -1, http.StatusServiceUnavailable, "30")
+	searcher := newTestSearcher(Data{}, Options{Retries: 3})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	website := Website{Name: "Flaky", BaseURL: server.URL + "/{}", ErrorType: "status_code"}
+	if result := searcher.SearchWebsite(ctx, website, existingUser); result.Status != StatusError {
+		t.Errorf("got status %s, want an error", result.Status)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("the search took %v, want it stopped when cancelled", elapsed)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"0", 0, true},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// Dates are converted to the time left until them
+	got, ok := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if !ok || got < 58*time.Minute || got > time.Hour {
+		t.Errorf("parseRetryAfter(date in an hour) = %v, %v", got, ok)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	// Delays are jittered below an upper bound that doubles with each attempt, up to maxRetryBackoff
+	transport := &retryTransport{backoff: 100 * time.Millisecond}
+	for attempt, limit := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		for range 50 {
+			if delay := transport.delay(attempt); delay <= 0 || delay > limit {
+				t.Fatalf("delay(%d) = %v, want at most %v", attempt, delay, limit)
+			}
+		}
+	}
+	if delay := transport.delay(40); delay <= 0 || delay > maxRetryBackoff {
+		t.Errorf("delay(40) = %v, want at most %v", delay, maxRetryBackoff)
+	}
+}
@@ -0,0 +1,108 @@
+package gosearch
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Search stages run by Searcher.Run, in order.
+const (
+	StageSites           = "sites"            // Username search across all websites
+	StageHudsonRock      = "hudsonrock"       // HudsonRock info-stealer lookup
+	StageBreachDirectory = "breach_directory" // Breach Directory lookup (requires an API key)
+	StageProxyNova       = "proxynova"        // ProxyNova compromised password lookup
+	StageDomains         = "domains"          // Domain search across common TLDs
+)
+
+// Report holds the complete outcome of a search run.
+type Report struct {
+	Username        string              `json:"username"`                   // Searched username
+	Websites        int                 `json:"websites"`                   // Number of websites searched
+	Profiles        []Result            `json:"profiles"`                   // Found and unverified profiles
+	HudsonRock      *HudsonRockResponse `json:"hudsonrock,omitempty"`       // HudsonRock results
+	BreachDirectory []Breach            `json:"breach_directory,omitempty"` // Breach Directory results
+	ProxyNova       *ProxyNova          `json:"proxynova,omitempty"`        // ProxyNova results
+	Domains         []string            `json:"domains"`                    // Found domains, serving a website
+	DomainResults   []DomainResult      `json:"domain_results"`             // Every checked domain
+	Errors          map[string]string   `json:"errors,omitempty"`           // Stage errors keyed by stage
+	Proxies         []ProxyStats        `json:"proxies,omitempty"`          // Proxy pool statistics, if a pool was used
+	Retries         int                 `json:"retries"`                    // Number of requests retried
+	Elapsed         string              `json:"elapsed"`                    // Total time taken
+}
+
+// Run runs every search stage for the username, streaming progress to handler, and returns the combined report.
+func (s *Searcher) Run(ctx context.Context, username string, handler Handler) Report {
+	report := Report{
+		Username:      username,
+		Websites:      len(s.data.Websites),
+		Profiles:      []Result{},
+		Domains:       []string{},
+		DomainResults: []DomainResult{},
+		Errors:        map[string]string{},
+	}
+
+	// Record start time for performance measurement
+	start := time.Now()
+
+	// Search websites concurrently, collecting found and unverified profiles
+	handler.StageStarted(StageSites)
+	for result := range s.Search(ctx, username) {
+		handler.SiteResult(result)
+		if result.Profile() {
+			report.Profiles = append(report.Profiles, result)
+		}
+	}
+	handler.StageFinished(StageSites, &report, nil)
+
+	// Search HudsonRock's database
+	handler.StageStarted(StageHudsonRock)
+	hudsonRock, err := s.HudsonRock(ctx, username)
+	if err == nil {
+		report.HudsonRock = &hudsonRock
+	}
+	finishStage(handler, StageHudsonRock, &report, err)
+
+	// Search Breach Directory if API key is provided
+	if s.options.BreachDirectoryAPIKey != "" {
+		handler.StageStarted(StageBreachDirectory)
+		breaches, err := s.SearchBreachDirectory(ctx, username, s.options.BreachDirectoryAPIKey)
+		report.BreachDirectory = breaches
+		finishStage(handler, StageBreachDirectory, &report, err)
+	}
+
+	// Search ProxyNova for compromised passwords
+	handler.StageStarted(StageProxyNova)
+	proxyNova, err := s.SearchProxyNova(ctx, username)
+	if err == nil {
+		report.ProxyNova = &proxyNova
+	}
+	finishStage(handler, StageProxyNova, &report, err)
+
+	// Search for domains associated with the username
+	handler.StageStarted(StageDomains)
+	domains, errs := s.CheckDomains(ctx, s.Domains(username))
+	report.DomainResults = append(report.DomainResults, domains...)
+	for _, domain := range domains {
+		if domain.Found() {
+			report.Domains = append(report.Domains, domain.Domain)
+		}
+	}
+	finishStage(handler, StageDomains, &report, errors.Join(errs...))
+
+	report.Elapsed = time.Since(start).String()
+	report.Retries = s.RetriesUsed()
+	if s.options.ProxyPool != nil {
+		report.Proxies = s.options.ProxyPool.Stats()
+	}
+	handler.RunFinished(&report)
+	return report
+}
+
+// finishStage records a stage error in the report and notifies the handler.
+func finishStage(handler Handler, stage string, report *Report, err error) {
+	if err != nil {
+		report.Errors[stage] = err.Error()
+	}
+	handler.StageFinished(stage, report, err)
+}
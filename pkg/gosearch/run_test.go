@@ -0,0 +1,125 @@
+package gosearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// stageRecorder is a Handler recording the events it receives.
+type stageRecorder struct {
+	events  []string
+	results []Result
+	report  *Report
+}
+
+func (r *stageRecorder) StageStarted(stage string) {
+	r.events = append(r.events, "start "+stage)
+}
+
+func (r *stageRecorder) SiteResult(result Result) {
+	r.results = append(r.results, result)
+}
+
+func (r *stageRecorder) StageFinished(stage string, report *Report, err error) {
+	r.events = append(r.events, "finish "+stage)
+}
+
+func (r *stageRecorder) RunFinished(report *Report) {
+	r.report = report
+}
+
+// newInternetServer starts a proxy standing in for the internet: sites.test serves the website fixtures,
+// breaches.test serves the breach APIs, lookup.test serves DNS and RDAP and only existingUser's .com domain
+// is registered.
+func newInternetServer(t *testing.T) *url.URL {
+	t.Helper()
+	sites, breaches := fixtureHandler(), breachHandler()
+	lookup := lookupHandler(map[string]testZone{existingUser + ".com": {addresses: []string{"192.0.2.1"}}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Host {
+		case "sites.test":
+			sites.ServeHTTP(w, r)
+		case "breaches.test":
+			breaches.ServeHTTP(w, r)
+		case "lookup.test":
+			lookup.ServeHTTP(w, r)
+		case existingUser + ".com":
+			w.Write([]byte("<html>" + r.Host + "</html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	proxy, _ := url.Parse(server.URL)
+	return proxy
+}
+
+func TestRun(t *testing.T) {
+	data := Data{Websites: []Website{
+		{Name: "status", BaseURL: "http://sites.test/status/{}", ErrorType: "status_code", ErrorCode: 404},
+		{Name: "message", BaseURL: "http://sites.test/message/{}", ErrorType: "errorMsg", ErrorMsg: "User not found"},
+		{Name: "presence", BaseURL: "http://sites.test/presence/{}", ErrorType: "profilePresence", ErrorMsg: "profile-header"},
+		{Name: "unverified", BaseURL: "http://sites.test/unverified/{}", ErrorType: "unknown"},
+	}}
+	endpoints := breachEndpoints("http://breaches.test")
+	lookup := lookupEndpoints("http://lookup.test")
+	endpoints.DNS, endpoints.RDAP = lookup.DNS, lookup.RDAP
+	searcher := newTestSearcher(data, Options{
+		BreachDirectoryAPIKey: testAPIKey,
+		Endpoints:             endpoints,
+		Proxy:                 newInternetServer(t),
+	})
+
+	recorder := &stageRecorder{}
+	report := searcher.Run(context.Background(), missingUser, recorder)
+	if len(report.Profiles) != 1 || report.Profiles[0].Status != StatusUnverified {
+		t.Errorf("missing user: got profiles %+v, want only the unverified one", report.Profiles)
+	}
+	if len(report.Domains) != 0 {
+		t.Errorf("missing user: got domains %v, want none", report.Domains)
+	}
+
+	recorder = &stageRecorder{}
+	report = searcher.Run(context.Background(), existingUser, recorder)
+
+	wantEvents := []string{
+		"start " + StageSites, "finish " + StageSites,
+		"start " + StageHudsonRock, "finish " + StageHudsonRock,
+		"start " + StageBreachDirectory, "finish " + StageBreachDirectory,
+		"start " + StageProxyNova, "finish " + StageProxyNova,
+		"start " + StageDomains, "finish " + StageDomains,
+	}
+	if !reflect.DeepEqual(recorder.events, wantEvents) {
+		t.Errorf("got events %v, want %v", recorder.events, wantEvents)
+	}
+	if len(recorder.results) != len(data.Websites) {
+		t.Errorf("got %d site results, want %d", len(recorder.results), len(data.Websites))
+	}
+	if recorder.report == nil || recorder.report.Username != existingUser {
+		t.Error("RunFinished not called with the report")
+	}
+
+	if len(report.Errors) != 0 {
+		t.Errorf("got errors %v", report.Errors)
+	}
+	if len(report.Profiles) != len(data.Websites) {
+		t.Errorf("got profiles %+v, want %d", report.Profiles, len(data.Websites))
+	}
+	if report.HudsonRock == nil || !report.HudsonRock.Compromised() {
+		t.Errorf("got HudsonRock %+v, want compromised", report.HudsonRock)
+	}
+	if len(report.BreachDirectory) != 2 {
+		t.Errorf("got Breach Directory %+v, want 2 breaches", report.BreachDirectory)
+	}
+	if report.ProxyNova == nil || report.ProxyNova.Count != 2 {
+		t.Errorf("got ProxyNova %+v, want 2 credentials", report.ProxyNova)
+	}
+	if want := []string{existingUser + ".com"}; !reflect.DeepEqual(report.Domains, want) {
+		t.Errorf("got domains %v, want %v", report.Domains, want)
+	}
+}
@@ -0,0 +1,480 @@
+package gosearch
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+// DefaultTimeout is the default time limit for a single request.
+const DefaultTimeout = 120 * time.Second
+
+// Options configures a Searcher.
+type Options struct {
+	NoFalsePositives      bool               // Skip websites whose results cannot be verified
+	BreachDirectoryAPIKey string             // Search Breach Directory when set
+	UserAgent             string             // User-Agent for requests, overriding the header profile's
+	HeaderProfile         string             // Header profile name, or ProfileRotate; defaults to DefaultHeaderProfile
+	Timeout               time.Duration      // Time limit for a single request; defaults to DefaultTimeout
+	Proxy                 *url.URL           // Proxy for every request; defaults to the environment's proxy settings
+	ProxyPool             *ProxyPool         // Rotating proxies for every request, used instead of Proxy when set
+	Retries               int                // Maximum retries per request after network errors, 429 or 5xx; 0 disables retries
+	RetryBackoff          time.Duration      // Base delay before the first retry; defaults to DefaultRetryBackoff
+	RetryBudget           int                // Maximum retries across every request of the Searcher; defaults to DefaultRetryBudget
+	HTTP2                 bool               // Negotiate HTTP/2 with servers that support it
+	Insecure              bool               // Skip TLS certificate verification, e.g. for lab targets with self-signed certificates
+	Secrets               Secrets            // Credentials injected into requests, keyed by website name
+	Cache                 *Cache             // On-disk response cache, if set
+	Record                *Recorder          // Records every response, if set
+	Replay                *Replayer          // Serves recorded responses instead of using the network, if set
+	Endpoints             Endpoints          // Breach database and domain lookup APIs; empty fields default to DefaultEndpoints
+	TLDs                  []string           // TLDs to check domains under, without a leading dot; defaults to DefaultTLDs
+	DomainTimeout         time.Duration      // Time limit for checking a single domain; defaults to DefaultDomainTimeout
+	DomainConcurrency     int                // Domains checked at once; defaults to DefaultDomainConcurrency
+	ParkingSignatures     *ParkingSignatures // Recognise parked and for-sale domains; defaults to DefaultParkingSignatures
+}
+
+// Searcher searches websites and breach databases for usernames.
+// A Searcher is safe for concurrent use and should be reused so connections are pooled.
+type Searcher struct {
+	data      Data
+	options   Options
+	transport http.RoundTripper
+
+	retryBudget *atomic.Int64 // Retries left before requests stop being retried
+
+	mu             sync.Mutex
+	siteTransports map[string]http.RoundTripper // Transports for websites with their own proxy or TLS settings
+}
+
+// NewSearcher creates a Searcher for the given websites and options.
+func NewSearcher(data Data, options Options) *Searcher {
+	if options.HeaderProfile == "" {
+		options.HeaderProfile = DefaultHeaderProfile
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = DefaultTimeout
+	}
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = DefaultRetryBackoff
+	}
+	if options.RetryBudget <= 0 {
+		options.RetryBudget = DefaultRetryBudget
+	}
+	if options.Endpoints.HudsonRock == "" {
+		options.Endpoints.HudsonRock = DefaultEndpoints.HudsonRock
+	}
+	if options.Endpoints.ProxyNova == "" {
+		options.Endpoints.ProxyNova = DefaultEndpoints.ProxyNova
+	}
+	if options.Endpoints.BreachDirectory == "" {
+		options.Endpoints.BreachDirectory = DefaultEndpoints.BreachDirectory
+	}
+	if options.Endpoints.Weakpass == "" {
+		options.Endpoints.Weakpass = DefaultEndpoints.Weakpass
+	}
+	if options.DomainTimeout <= 0 {
+		options.DomainTimeout = DefaultDomainTimeout
+	}
+	if options.DomainConcurrency <= 0 {
+		options.DomainConcurrency = DefaultDomainConcurrency
+	}
+	if options.ParkingSignatures == nil {
+		options.ParkingSignatures = &DefaultParkingSignatures
+	}
+	if options.Endpoints.DNS == "" {
+		options.Endpoints.DNS = DefaultEndpoints.DNS
+	}
+	if options.Endpoints.RDAP == "" {
+		options.Endpoints.RDAP = DefaultEndpoints.RDAP
+	}
+
+	s := &Searcher{
+		data:           data,
+		options:        options,
+		retryBudget:    &atomic.Int64{},
+		siteTransports: map[string]http.RoundTripper{},
+	}
+	s.retryBudget.Store(int64(options.RetryBudget))
+
+	// The Searcher's own settings are always valid; only websites can select unknown TLS settings
+	var transport http.RoundTripper
+	if options.ProxyPool != nil {
+		base, _ := s.newTransport(nil, TLSSettings{})
+		transport = options.ProxyPool.Transport(base)
+	} else {
+		transport, _ = s.newTransport(options.Proxy, TLSSettings{})
+	}
+	s.transport = s.wrap(transport)
+	return s
+}
+
+// Websites returns the websites the Searcher searches.
+func (s *Searcher) Websites() []Website {
+	return s.data.Websites
+}
+
+// client returns an HTTP client for requests to the website, honouring its proxy and redirect settings
+// and keeping cookies in the session's cookie jar if ctx carries one.
+func (s *Searcher) client(ctx context.Context, website Website) (*http.Client, error) {
+	transport, err := s.transportFor(website)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Timeout:   s.options.Timeout,
+		Transport: transport,
+		Jar:       nil,
+	}
+	if sess := sessionFrom(ctx); sess != nil {
+		client.Jar = sess.jar
+	}
+
+	// Disable redirects if specified
+	if !website.FollowRedirects {
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	} else {
+		client.CheckRedirect = s.followRedirect(website)
+	}
+
+	return client, nil
+}
+
+// newRequest creates a browser-like GET request for the website.
+func (s *Searcher) newRequest(ctx context.Context, website Website, url string) (*http.Request, error) {
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set User-Agent
+	userAgent := s.options.UserAgent
+	if website.UserAgent != "" {
+		userAgent = website.UserAgent
+	}
+
+	// Set request headers from the website's header profile
+	profile, err := s.headerProfile(website)
+	if err != nil {
+		return nil, err
+	}
+	profile.Apply(req, userAgent)
+
+	// Add cookies if specified
+	for _, cookie := range website.Cookies {
+		req.AddCookie(&http.Cookie{
+			Name:  cookie.Name,
+			Value: cookie.Value,
+		})
+	}
+
+	// Add the website's secrets if the request goes to their host, keeping authenticated responses out of the cache and recordings
+	if secret, ok := s.options.Secrets.forURL(website.Name, req.URL); ok {
+		secret.apply(req)
+		req = req.WithContext(withSecrets(req.Context()))
+	}
+
+	// Send the session's token if the website expects it in a header
+	if sess := sessionFrom(ctx); sess != nil && website.Bootstrap.TokenHeader != "" {
+		req.Header.Set(website.Bootstrap.TokenHeader, sess.token)
+	}
+
+	return req, nil
+}
+
+// ReadBody reads a response body, decompressing it according to its Content-Encoding.
+func ReadBody(res *http.Response) ([]byte, error) {
+	reader, err := decodeBody(res)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Read response body
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	return body, nil
+}
+
+// decodeBody returns a reader of the response body, decompressing it according to its Content-Encoding.
+func decodeBody(res *http.Response) (io.ReadCloser, error) {
+	switch res.Header.Get("Content-Encoding") {
+	case "gzip":
+		gzReader, err := gzip.NewReader(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error creating gzip reader: %w", err)
+		}
+		return gzReader, nil
+	case "deflate":
+		zlibReader, err := zlib.NewReader(res.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error creating deflate reader: %w", err)
+		}
+		return zlibReader, nil
+	case "br":
+		return io.NopCloser(brotli.NewReader(res.Body)), nil
+	default:
+		return res.Body, nil
+	}
+}
+
+// Search searches every configured website concurrently, sending each result on the returned channel.
+// The channel is buffered for every website and closed once every website has been searched.
+func (s *Searcher) Search(ctx context.Context, username string) <-chan Result {
+	results := make(chan Result, len(s.data.Websites))
+
+	var wg sync.WaitGroup
+	wg.Add(len(s.data.Websites))
+
+	// Iterate over websites
+	for _, website := range s.data.Websites {
+		// Run search in a goroutine
+		go func(website Website) {
+			defer wg.Done()
+			results <- s.SearchWebsite(ctx, website, username)
+		}(website)
+	}
+
+	// Close the channel once every search has finished
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
+
+// probeURL returns the URL requested to check the website for the username, establishing the website's
+// session first if it needs one. The returned context carries the session.
+func (s *Searcher) probeURL(ctx context.Context, website Website, username string) (context.Context, string, error) {
+	var url string
+
+	// Use probe URL if specified, otherwise use base URL
+	if website.URLProbe != "" {
+		url = BuildURL(website.URLProbe, username)
+	} else {
+		url = BuildURL(website.BaseURL, username)
+	}
+
+	// Establish a session first if the website needs one. Sessions must be fresh, so neither the bootstrap
+	// request nor the probe made with its cookies and token is cached.
+	if website.Bootstrap != nil {
+		ctx = withoutCache(ctx)
+		sess, err := s.bootstrap(ctx, website, username)
+		if err != nil {
+			return nil, "", fmt.Errorf("error bootstrapping session: %w", err)
+		}
+		ctx = withSession(ctx, sess)
+		url = insertToken(url, sess)
+	}
+
+	return ctx, url, nil
+}
+
+// SearchWebsite checks a single website for the username using the website's error type.
+func (s *Searcher) SearchWebsite(ctx context.Context, website Website, username string) Result {
+	ctx, url, err := s.probeURL(ctx, website, username)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+
+	// Handle different error types
+	switch website.ErrorType {
+	case "status_code":
+		return s.MakeRequestWithErrorCode(ctx, website, url, username)
+	case "errorMsg":
+		return s.MakeRequestWithErrorMsg(ctx, website, url, username)
+	case "profilePresence":
+		return s.MakeRequestWithProfilePresence(ctx, website, url, username)
+	case "response_url":
+		return s.MakeRequestWithResponseURL(ctx, website, url, username)
+	default:
+		// Unverified profiles are only reported if false positives are allowed
+		if s.options.NoFalsePositives {
+			return NewResult(website, username, StatusSkipped, nil)
+		}
+		return NewResult(website, username, StatusUnverified, nil)
+	}
+}
+
+// NewResult creates a Result for the website's profile URL with the given status and optional error.
+func NewResult(website Website, username string, status string, err error) Result {
+	result := Result{
+		Username: username,
+		Website:  website.Name,
+		URL:      BuildURL(website.BaseURL, username),
+		Status:   status,
+		NSFW:     website.NSFW,
+		Catalog:  website.Catalog,
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// MakeRequestWithResponseURL checks for profile existence by comparing the response URL.
+func (s *Searcher) MakeRequestWithResponseURL(ctx context.Context, website Website, url string, username string) Result {
+	// Some websites always return a 200 for existing and non-existing profiles.
+	// If we do not follow redirects, we could get a 301 for existing profiles and 302 for non-existing profiles.
+	// That is why we have the follow_redirects in our website struct.
+	// However, sometimes the website returns 301 for existing profiles and non-existing profiles.
+	// This means even if we do not follow redirects, we still get false positives.
+	// To mitigate this, we can examine the response url to check for non-existing profiles.
+	// Usually, a response url pointing to where the profile should be is returned for existing profiles.
+	// If the response url is not pointing to where the profile should be, then the profile does not exist.
+
+	// Create request
+	req, err := s.newRequest(ctx, website, url)
+	if err != nil {
+		return NewResult(website, username, StatusError, fmt.Errorf("error creating request in function MakeRequestWithResponseURL: %w", err))
+	}
+
+	// Send request
+	client, err := s.client(ctx, website)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	defer res.Body.Close()
+
+	// Check for error status codes
+	if res.StatusCode >= 400 {
+		return NewResult(website, username, StatusNotFound, nil)
+	}
+
+	// Compare response URL with expected URL
+	formattedResponseURL := BuildURL(website.ResponseURL, username)
+	if res.Request.URL.String() != formattedResponseURL {
+		return NewResult(website, username, StatusFound, nil)
+	}
+	return NewResult(website, username, StatusNotFound, nil)
+}
+
+// MakeRequestWithErrorCode checks for profile existence by comparing HTTP status codes.
+func (s *Searcher) MakeRequestWithErrorCode(ctx context.Context, website Website, url string, username string) Result {
+	// Create request
+	req, err := s.newRequest(ctx, website, url)
+	if err != nil {
+		return NewResult(website, username, StatusError, fmt.Errorf("error creating request in function MakeRequestWithErrorCode: %w", err))
+	}
+
+	// Send request
+	client, err := s.client(ctx, website)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	defer res.Body.Close()
+
+	// Check for error status codes
+	if res.StatusCode >= 400 {
+		return NewResult(website, username, StatusNotFound, nil)
+	}
+
+	// Check if status code differs from error code
+	if res.StatusCode != website.ErrorCode {
+		return NewResult(website, username, StatusFound, nil)
+	}
+	return NewResult(website, username, StatusNotFound, nil)
+}
+
+// MakeRequestWithErrorMsg checks for profile existence by searching for an error message in the response body.
+func (s *Searcher) MakeRequestWithErrorMsg(ctx context.Context, website Website, url string, username string) Result {
+	// Create request
+	req, err := s.newRequest(ctx, website, url)
+	if err != nil {
+		return NewResult(website, username, StatusError, fmt.Errorf("error creating request in function MakeRequestWithErrorMsg: %w", err))
+	}
+
+	// Send request
+	client, err := s.client(ctx, website)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	defer res.Body.Close()
+
+	// Check for error status codes
+	if res.StatusCode >= 400 {
+		return NewResult(website, username, StatusNotFound, nil)
+	}
+
+	// Read response body
+	body, err := ReadBody(res)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+
+	// Check for error message
+	if !strings.Contains(string(body), website.ErrorMsg) {
+		return NewResult(website, username, StatusFound, nil)
+	}
+	return NewResult(website, username, StatusNotFound, nil)
+}
+
+// MakeRequestWithProfilePresence checks for profile existence by searching for a profile indicator in the response body.
+func (s *Searcher) MakeRequestWithProfilePresence(ctx context.Context, website Website, url string, username string) Result {
+	// Some websites have an indicator that a profile exists
+	// but do not have an indicator when a profile does not exist.
+	// If a profile indicator is not found, we can assume that the profile does not exist.
+
+	// Create request
+	req, err := s.newRequest(ctx, website, url)
+	if err != nil {
+		return NewResult(website, username, StatusError, fmt.Errorf("error creating request in function MakeRequestWithProfilePresence: %w", err))
+	}
+
+	// Send request
+	client, err := s.client(ctx, website)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+	defer res.Body.Close()
+
+	// Check for error status codes
+	if res.StatusCode >= 400 {
+		return NewResult(website, username, StatusNotFound, nil)
+	}
+
+	// Read response body
+	body, err := ReadBody(res)
+	if err != nil {
+		return NewResult(website, username, StatusError, err)
+	}
+
+	// Check for profile indicator
+	if strings.Contains(string(body), website.ErrorMsg) {
+		return NewResult(website, username, StatusFound, nil)
+	}
+	return NewResult(website, username, StatusNotFound, nil)
+}
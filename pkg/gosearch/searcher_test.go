@@ -0,0 +1,223 @@
+package gosearch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSearchWebsite(t *testing.T) {
+	server := newFixtureServer(t)
+
+	tests := []struct {
+		name    string
+		website Website
+	}{
+		{
+			name: "status_code",
+			website: Website{
+				BaseURL:   server.URL + "/status/{}",
+				ErrorType: "status_code",
+				ErrorCode: 404,
+			},
+		},
+		{
+			name: "status_code with error code below 400",
+			website: Website{
+				BaseURL:   server.URL + "/code/{}",
+				ErrorType: "status_code",
+				ErrorCode: 302,
+			},
+		},
+		{
+			name: "status_code following redirects",
+			website: Website{
+				BaseURL:         server.URL + "/always-redirect/{}",
+				FollowRedirects: true,
+				ErrorType:       "status_code",
+				ErrorCode:       404,
+			},
+		},
+		{
+			name: "errorMsg",
+			website: Website{
+				BaseURL:   server.URL + "/message/{}",
+				ErrorType: "errorMsg",
+				ErrorMsg:  "User not found",
+			},
+		},
+		{
+			name: "profilePresence",
+			website: Website{
+				BaseURL:   server.URL + "/presence/{}",
+				ErrorType: "profilePresence",
+				ErrorMsg:  "profile-header",
+			},
+		},
+		{
+			name: "response_url",
+			website: Website{
+				BaseURL:         server.URL + "/moved/{}",
+				FollowRedirects: true,
+				ErrorType:       "response_url",
+				ResponseURL:     server.URL + "/notfound",
+			},
+		},
+		{
+			name: "url_probe",
+			website: Website{
+				BaseURL:   "https://example.com/{}",
+				URLProbe:  server.URL + "/status/{}",
+				ErrorType: "status_code",
+				ErrorCode: 404,
+			},
+		},
+		{
+			name: "gzip",
+			website: Website{
+				BaseURL:   server.URL + "/compressed/gzip/{}",
+				ErrorType: "errorMsg",
+				ErrorMsg:  "User not found",
+			},
+		},
+		{
+			name: "deflate",
+			website: Website{
+				BaseURL:   server.URL + "/compressed/deflate/{}",
+				ErrorType: "errorMsg",
+				ErrorMsg:  "User not found",
+			},
+		},
+		{
+			name: "br",
+			website: Website{
+				BaseURL:   server.URL + "/compressed/br/{}",
+				ErrorType: "errorMsg",
+				ErrorMsg:  "User not found",
+			},
+		},
+		{
+			name: "cookies",
+			website: Website{
+				BaseURL:   server.URL + "/cookie/{}",
+				ErrorType: "errorMsg",
+				ErrorMsg:  "User not found",
+				Cookies:   []Cookie{{Name: "consent", Value: "yes"}},
+			},
+		},
+	}
+
+	searcher := newTestSearcher(Data{}, Options{})
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.website.Name = test.name
+
+			result := searcher.SearchWebsite(context.Background(), test.website, existingUser)
+			if result.Status != StatusFound {
+				t.Errorf("existing profile: got status %q (error %q), want %q", result.Status, result.Error, StatusFound)
+			}
+			if want := BuildURL(test.website.BaseURL, existingUser); result.URL != want {
+				t.Errorf("existing profile: got URL %q, want %q", result.URL, want)
+			}
+
+			result = searcher.SearchWebsite(context.Background(), test.website, missingUser)
+			if result.Status != StatusNotFound {
+				t.Errorf("missing profile: got status %q (error %q), want %q", result.Status, result.Error, StatusNotFound)
+			}
+		})
+	}
+}
+
+func TestSearchWebsiteWithoutRedirects(t *testing.T) {
+	server := newFixtureServer(t)
+	searcher := newTestSearcher(Data{}, Options{})
+
+	// Without following redirects, every profile of a site that always redirects looks like it exists
+	website := Website{
+		Name:      "no redirects",
+		BaseURL:   server.URL + "/always-redirect/{}",
+		ErrorType: "status_code",
+		ErrorCode: 404,
+	}
+	for _, user := range []string{existingUser, missingUser} {
+		if result := searcher.SearchWebsite(context.Background(), website, user); result.Status != StatusFound {
+			t.Errorf("%s: got status %q, want %q", user, result.Status, StatusFound)
+		}
+	}
+}
+
+func TestSearchWebsiteMissingCookie(t *testing.T) {
+	server := newFixtureServer(t)
+	searcher := newTestSearcher(Data{}, Options{})
+
+	website := Website{
+		Name:      "cookies",
+		BaseURL:   server.URL + "/cookie/{}",
+		ErrorType: "errorMsg",
+		ErrorMsg:  "User not found",
+	}
+	if result := searcher.SearchWebsite(context.Background(), website, existingUser); result.Status != StatusNotFound {
+		t.Errorf("got status %q, want %q", result.Status, StatusNotFound)
+	}
+}
+
+func TestSearchWebsiteUnverified(t *testing.T) {
+	website := Website{
+		Name:      "unverified",
+		BaseURL:   "https://example.com/{}",
+		ErrorType: "unknown",
+	}
+
+	searcher := newTestSearcher(Data{}, Options{})
+	if result := searcher.SearchWebsite(context.Background(), website, existingUser); result.Status != StatusUnverified {
+		t.Errorf("got status %q, want %q", result.Status, StatusUnverified)
+	}
+
+	searcher = newTestSearcher(Data{}, Options{NoFalsePositives: true})
+	if result := searcher.SearchWebsite(context.Background(), website, existingUser); result.Status != StatusSkipped {
+		t.Errorf("with NoFalsePositives: got status %q, want %q", result.Status, StatusSkipped)
+	}
+}
+
+func TestSearchWebsiteError(t *testing.T) {
+	server := newFixtureServer(t)
+	url := server.URL
+	server.Close()
+
+	website := Website{
+		Name:      "offline",
+		BaseURL:   url + "/status/{}",
+		ErrorType: "status_code",
+		ErrorCode: 404,
+	}
+	result := newTestSearcher(Data{}, Options{}).SearchWebsite(context.Background(), website, existingUser)
+	if result.Status != StatusError || result.Error == "" {
+		t.Errorf("got status %q with error %q, want %q with an error", result.Status, result.Error, StatusError)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	server := newFixtureServer(t)
+	data := Data{Websites: []Website{
+		{Name: "status", BaseURL: server.URL + "/status/{}", ErrorType: "status_code", ErrorCode: 404},
+		{Name: "message", BaseURL: server.URL + "/message/{}", ErrorType: "errorMsg", ErrorMsg: "User not found"},
+		{Name: "presence", BaseURL: server.URL + "/presence/{}", ErrorType: "profilePresence", ErrorMsg: "profile-header"},
+	}}
+
+	found := map[string]bool{}
+	for result := range newTestSearcher(data, Options{}).Search(context.Background(), existingUser) {
+		if result.Status == StatusFound {
+			found[result.Website] = true
+		}
+	}
+	for _, website := range data.Websites {
+		if !found[website.Name] {
+			t.Errorf("%s: profile not found", website.Name)
+		}
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	if got := BuildURL("https://example.com/{}?tab={}", existingUser); got != "https://example.com/alice?tab={}" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,164 @@
+package gosearch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// SiteSecret holds credentials injected into every request to a website.
+// A secret is bound to Host: it is only sent to that host and its subdomains, whatever URL the catalog gives the website.
+// Secrets are never included in results, reports or errors; String redacts them so they cannot be printed by accident.
+type SiteSecret struct {
+	Host        string            `json:"host"`                   // Host the secret belongs to, such as "github.com"
+	Cookies     []Cookie          `json:"cookies,omitempty"`      // Session cookies
+	BearerToken string            `json:"bearer_token,omitempty"` // Sent as "Authorization: Bearer <token>"
+	Headers     map[string]string `json:"headers,omitempty"`      // Extra headers, such as API keys
+}
+
+// String redacts the secret.
+func (s SiteSecret) String() string {
+	return "[REDACTED]"
+}
+
+// GoString redacts the secret.
+func (s SiteSecret) GoString() string {
+	return "[REDACTED]"
+}
+
+// Secrets holds site secrets keyed by website name, matched case-insensitively.
+type Secrets map[string]SiteSecret
+
+// String lists the websites with secrets without revealing the secrets.
+func (s Secrets) String() string {
+	return fmt.Sprintf("secrets for %d websites", len(s))
+}
+
+// GoString lists the websites with secrets without revealing the secrets.
+func (s Secrets) GoString() string {
+	return s.String()
+}
+
+// ReadSecrets reads a JSON object mapping website names to their secrets.
+func ReadSecrets(r io.Reader) (Secrets, error) {
+	// Errors are rebuilt without the decoder's message so a malformed file cannot leak secrets into the terminal
+	var secrets Secrets
+	if err := json.NewDecoder(r).Decode(&secrets); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, fmt.Errorf("error parsing secrets: invalid JSON at offset %d", syntaxErr.Offset)
+		}
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errors.New("error parsing secrets: unexpected end of JSON")
+		}
+		return nil, errors.New("error parsing secrets: expected an object mapping website names to secrets")
+	}
+	for name, secret := range secrets {
+		if secret.Host == "" {
+			return nil, fmt.Errorf("error parsing secrets: the secret for %s has no host", name)
+		}
+		if strings.ContainsAny(secret.Host, ":/") {
+			return nil, fmt.Errorf("error parsing secrets: the secret for %s has host %q; expected a bare host name such as github.com", name, secret.Host)
+		}
+	}
+	return secrets, nil
+}
+
+// lookup returns the secret for the website with the given name, matching the name case-insensitively.
+func (s Secrets) lookup(name string) (SiteSecret, bool) {
+	if secret, ok := s[name]; ok {
+		return secret, true
+	}
+	for key, secret := range s {
+		if strings.EqualFold(key, name) {
+			return secret, true
+		}
+	}
+	return SiteSecret{}, false
+}
+
+// forURL returns the website's secret if the URL is on the host the secret is bound to.
+func (s Secrets) forURL(name string, u *url.URL) (SiteSecret, bool) {
+	secret, ok := s.lookup(name)
+	if !ok || !sameDomain(u.Hostname(), secret.Host) {
+		return SiteSecret{}, false
+	}
+	return secret, true
+}
+
+// Check returns a warning for every secret that will never be sent:
+// secrets naming websites missing from the data, and secrets bound to a host other than their website's.
+func (s Secrets) Check(data Data) []string {
+	names := make([]string, 0, len(s))
+	for name := range s {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var warnings []string
+	for _, name := range names {
+		i := data.Index(name)
+		if i < 0 {
+			warnings = append(warnings, fmt.Sprintf("The secret for %s names an unknown website; it will not be sent.", name))
+			continue
+		}
+		website := data.Websites[i]
+		template := website.BaseURL
+		if website.URLProbe != "" {
+			template = website.URLProbe
+		}
+		target, err := url.Parse(BuildURL(template, "username"))
+		if err != nil || !sameDomain(target.Hostname(), s[name].Host) {
+			warnings = append(warnings, fmt.Sprintf("The secret for %s is bound to %s, but %s is searched at another host; it will not be sent.", name, s[name].Host, website.Name))
+		}
+	}
+	return warnings
+}
+
+// apply adds the secret's cookies and headers to the request.
+func (s SiteSecret) apply(req *http.Request) {
+	for _, cookie := range s.Cookies {
+		req.AddCookie(&http.Cookie{
+			Name:  cookie.Name,
+			Value: cookie.Value,
+		})
+	}
+	if s.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.BearerToken)
+	}
+	for key, value := range s.Headers {
+		req.Header.Set(key, value)
+	}
+}
+
+// secretKey is the context key marking requests that carry secrets.
+type secretKey struct{}
+
+// withSecrets returns a context whose requests carry secrets: they are neither cached nor recorded.
+func withSecrets(ctx context.Context) context.Context {
+	return context.WithValue(withoutCache(ctx), secretKey{}, true)
+}
+
+// followRedirect returns a redirect policy that follows up to 10 redirects like http.Client's default policy,
+// dropping the website's secret headers when a redirect leaves the original host.
+// http.Client already drops the Authorization and Cookie headers in that case.
+func (s *Searcher) followRedirect(website Website) func(req *http.Request, via []*http.Request) error {
+	secret, _ := s.options.Secrets.lookup(website.Name)
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		if req.URL.Hostname() != via[0].URL.Hostname() {
+			for key := range secret.Headers {
+				req.Header.Del(key)
+			}
+		}
+		return nil
+	}
+}
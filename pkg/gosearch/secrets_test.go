@@ -0,0 +1,195 @@
+package gosearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// secretServer is a proxy standing in for every website, recording the credentials each host received.
+type secretServer struct {
+	proxy *url.URL
+
+	mu       sync.Mutex
+	received map[string]http.Header
+}
+
+func newSecretServer(t *testing.T) *secretServer {
+	s := &secretServer{received: make(map[string]http.Header)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.received[r.Host] = r.Header.Clone()
+		s.mu.Unlock()
+		if r.Host == "redirect.test" {
+			http.Redirect(w, r, "http://elsewhere.test/"+strings.TrimPrefix(r.URL.Path, "/"), http.StatusFound)
+			return
+		}
+		w.Write([]byte("<html>profile</html>"))
+	}))
+	t.Cleanup(server.Close)
+	s.proxy, _ = url.Parse(server.URL)
+	return s
+}
+
+// header returns the header a host received, or nil if the host was never requested.
+func (s *secretServer) header(host string) http.Header {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.received[host]
+}
+
+func (s *secretServer) search(t *testing.T, website Website, secrets Secrets) {
+	t.Helper()
+	searcher := newTestSearcher(Data{}, Options{Proxy: s.proxy, Secrets: secrets})
+	result := searcher.SearchWebsite(context.Background(), website, existingUser)
+	if result.Error != "" {
+		t.Fatalf("SearchWebsite: %s", result.Error)
+	}
+}
+
+var testSecret = SiteSecret{
+	Host:        "github.test",
+	Cookies:     []Cookie{{Name: "session", Value: "cookie-secret"}},
+	BearerToken: "token-secret",
+	Headers:     map[string]string{"X-Api-Key": "key-secret"},
+}
+
+// carriesSecret reports whether the header carries any part of testSecret.
+func carriesSecret(header http.Header) bool {
+	return strings.Contains(header.Get("Cookie"), "cookie-secret") ||
+		strings.Contains(header.Get("Authorization"), "token-secret") ||
+		header.Get("X-Api-Key") != ""
+}
+
+func TestSecretsAttached(t *testing.T) {
+	server := newSecretServer(t)
+	secrets := Secrets{"github": testSecret}
+
+	// The name is matched case-insensitively and subdomains of the host get the secret too
+	for _, host := range []string{"github.test", "api.github.test"} {
+		server.search(t, Website{
+			Name:      "GitHub",
+			BaseURL:   "http://" + host + "/{}",
+			ErrorType: "status_code",
+			ErrorCode: 404,
+		}, secrets)
+
+		header := server.header(host)
+		if header.Get("Authorization") != "Bearer token-secret" {
+			t.Errorf("%s: Authorization = %q, want the bearer token", host, header.Get("Authorization"))
+		}
+		if header.Get("X-Api-Key") != "key-secret" {
+			t.Errorf("%s: X-Api-Key = %q, want the secret header", host, header.Get("X-Api-Key"))
+		}
+		if !strings.Contains(header.Get("Cookie"), "session=cookie-secret") {
+			t.Errorf("%s: Cookie = %q, want the secret cookie", host, header.Get("Cookie"))
+		}
+	}
+}
+
+func TestSecretsOtherHost(t *testing.T) {
+	server := newSecretServer(t)
+
+	// A catalog pointing GitHub somewhere else must not receive its secret
+	for _, host := range []string{"evil.test", "github.test.evil.test", "notgithub.test"} {
+		server.search(t, Website{
+			Name:      "GitHub",
+			BaseURL:   "http://" + host + "/{}",
+			ErrorType: "status_code",
+			ErrorCode: 404,
+		}, Secrets{"GitHub": testSecret})
+
+		header := server.header(host)
+		if header == nil {
+			t.Fatalf("%s was not requested", host)
+		}
+		if carriesSecret(header) {
+			t.Errorf("%s received the secret bound to github.test: %v", host, header)
+		}
+	}
+}
+
+func TestSecretsRedirect(t *testing.T) {
+	server := newSecretServer(t)
+	secret := testSecret
+	secret.Host = "redirect.test"
+
+	server.search(t, Website{
+		Name:            "Redirect",
+		BaseURL:         "http://redirect.test/{}",
+		FollowRedirects: true,
+		ErrorType:       "status_code",
+		ErrorCode:       404,
+	}, Secrets{"Redirect": secret})
+
+	if header := server.header("redirect.test"); !carriesSecret(header) {
+		t.Errorf("redirect.test did not receive its secret: %v", header)
+	}
+	header := server.header("elsewhere.test")
+	if header == nil {
+		t.Fatal("the redirect was not followed")
+	}
+	if carriesSecret(header) {
+		t.Errorf("the secret followed a redirect to another host: %v", header)
+	}
+}
+
+func TestReadSecrets(t *testing.T) {
+	secrets, err := ReadSecrets(strings.NewReader(`{"GitHub": {"host": "github.com", "bearer_token": "token-secret"}}`))
+	if err != nil {
+		t.Fatalf("ReadSecrets: %v", err)
+	}
+	if secrets["GitHub"].Host != "github.com" || secrets["GitHub"].BearerToken != "token-secret" {
+		t.Errorf("ReadSecrets = %#v", secrets["GitHub"])
+	}
+
+	tests := []struct {
+		name string
+		json string
+	}{
+		{"missing host", `{"GitHub": {"bearer_token": "token-secret"}}`},
+		{"host with scheme", `{"GitHub": {"host": "https://github.com", "bearer_token": "token-secret"}}`},
+		{"invalid JSON", `{"GitHub": {"bearer_token": "token-secret"`},
+		{"wrong type", `{"GitHub": "token-secret"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadSecrets(strings.NewReader(tt.json))
+			if err == nil {
+				t.Fatal("ReadSecrets succeeded")
+			}
+			if strings.Contains(err.Error(), "token-secret") {
+				t.Errorf("error leaks the secret: %v", err)
+			}
+		})
+	}
+}
+
+func TestSecretsCheck(t *testing.T) {
+	data := Data{Websites: []Website{
+		{Name: "GitHub", BaseURL: "https://github.test/{}"},
+		{Name: "Moved", BaseURL: "https://moved.test/{}"},
+		{Name: "Probed", BaseURL: "https://probed.test/{}", URLProbe: "https://api.probed.test/users/{}"},
+	}}
+	secrets := Secrets{
+		"github":  {Host: "github.test"},
+		"Moved":   {Host: "original.test"},
+		"Probed":  {Host: "probed.test"},
+		"Unknown": {Host: "unknown.test"},
+	}
+
+	warnings := secrets.Check(data)
+	if len(warnings) != 2 {
+		t.Fatalf("Check = %q, want warnings for Moved and Unknown", warnings)
+	}
+	if !strings.Contains(warnings[0], "Moved") || !strings.Contains(warnings[0], "original.test") {
+		t.Errorf("warnings[0] = %q, want the host mismatch for Moved", warnings[0])
+	}
+	if !strings.Contains(warnings[1], "Unknown") || !strings.Contains(warnings[1], "unknown website") {
+		t.Errorf("warnings[1] = %q, want the unknown website", warnings[1])
+	}
+}
@@ -0,0 +1,105 @@
+package gosearch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Bootstrap describes a request made before probing a website to establish a session.
+// Cookies set by the bootstrap request are kept in a cookie jar for the probe, and a token such as a
+// CSRF token can be extracted from the response and sent with the probe.
+type Bootstrap struct {
+	URL         string `json:"url"`                    // Page to request first; {} is replaced with the username
+	TokenRegex  string `json:"token_regex,omitempty"`  // Regular expression whose first group extracts the token from the response body
+	TokenCookie string `json:"token_cookie,omitempty"` // Cookie whose value is used as the token
+	TokenHeader string `json:"token_header,omitempty"` // Header the token is sent in on the probe request
+}
+
+// session holds the state established by a website's bootstrap request.
+type session struct {
+	jar   http.CookieJar // Cookies set by the bootstrap request
+	token string         // Token extracted from the bootstrap response
+}
+
+// sessionKey is the context key holding a website's session.
+type sessionKey struct{}
+
+// withSession returns a context carrying the session for a website's probe request.
+func withSession(ctx context.Context, sess *session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// sessionFrom returns the session carried by ctx, or nil if there is none.
+func sessionFrom(ctx context.Context) *session {
+	sess, _ := ctx.Value(sessionKey{}).(*session)
+	return sess
+}
+
+// bootstrap makes the website's bootstrap request, returning the session it established.
+func (s *Searcher) bootstrap(ctx context.Context, website Website, username string) (*session, error) {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create request
+	req, err := s.newRequest(ctx, website, BuildURL(website.Bootstrap.URL, username))
+	if err != nil {
+		return nil, err
+	}
+
+	// Send request, following redirects so cookies set along the way are kept
+	client, err := s.client(ctx, website)
+	if err != nil {
+		return nil, err
+	}
+	client.Jar = jar
+	client.CheckRedirect = s.followRedirect(website)
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	sess := &session{jar: jar}
+
+	// Extract the token from the response body or a cookie
+	switch {
+	case website.Bootstrap.TokenRegex != "":
+		re, err := regexp.Compile(website.Bootstrap.TokenRegex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token_regex: %w", err)
+		}
+		body, err := ReadBody(res)
+		if err != nil {
+			return nil, err
+		}
+		match := re.FindSubmatch(body)
+		if len(match) < 2 {
+			return nil, errors.New("token not found in bootstrap response")
+		}
+		sess.token = string(match[1])
+	case website.Bootstrap.TokenCookie != "":
+		for _, cookie := range jar.Cookies(res.Request.URL) {
+			if cookie.Name == website.Bootstrap.TokenCookie {
+				sess.token = cookie.Value
+			}
+		}
+		if sess.token == "" {
+			return nil, fmt.Errorf("cookie %q not set by bootstrap response", website.Bootstrap.TokenCookie)
+		}
+	}
+
+	return sess, nil
+}
+
+// insertToken replaces the {token} placeholder in a probe URL with the session's token.
+func insertToken(probeURL string, sess *session) string {
+	return strings.ReplaceAll(probeURL, "{token}", url.QueryEscape(sess.token))
+}
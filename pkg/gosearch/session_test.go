@@ -0,0 +1,169 @@
+package gosearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSessionServer starts a server whose profiles can only be requested with the session cookie and token
+// handed out by its /start page, returning the server and a counter of bootstrap requests.
+func newSessionServer(t *testing.T) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var bootstraps atomic.Int64
+	mux := http.NewServeMux()
+
+	// The start page sets the session cookie and a cookie token, and embeds a CSRF token
+	mux.HandleFunc("GET /start/{user}", func(w http.ResponseWriter, r *http.Request) {
+		bootstraps.Add(1)
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "session-1", Path: "/"})
+		http.SetCookie(w, &http.Cookie{Name: "xsrf", Value: "cookie token", Path: "/"})
+		io.WriteString(w, `<html><meta name="csrf" content="csrf-1"></html>`)
+	})
+
+	// The home page redirects to the start page after setting a cookie of its own
+	mux.HandleFunc("GET /home/{user}", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "visited", Value: "yes", Path: "/"})
+		http.Redirect(w, r, "/start/"+r.PathValue("user"), http.StatusFound)
+	})
+
+	// Profiles need the session cookie and the CSRF token in a header
+	mux.HandleFunc("GET /profile/{user}", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("sid"); err != nil || cookie.Value != "session-1" || r.Header.Get("X-CSRF-Token") != "csrf-1" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.PathValue("user") != existingUser {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "<html>profile</html>")
+	})
+
+	// The API needs the session cookie, the cookie set on the home page and the cookie token in the URL
+	mux.HandleFunc("GET /api/{user}", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("sid")
+		_, visitedErr := r.Cookie("visited")
+		if err != nil || visitedErr != nil || r.URL.Query().Get("token") != "cookie token" {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.PathValue("user") != existingUser || cookie.Value != "session-1" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "<html>profile</html>")
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &bootstraps
+}
+
+func TestBootstrap(t *testing.T) {
+	server, _ := newSessionServer(t)
+
+	tests := []struct {
+		name    string
+		website Website
+	}{
+		{
+			name: "token from the body sent in a header",
+			website: Website{
+				BaseURL:   server.URL + "/profile/{}",
+				ErrorType: "status_code",
+				ErrorCode: 404,
+				Bootstrap: &Bootstrap{
+					URL:         server.URL + "/start/{}",
+					TokenRegex:  `name="csrf" content="([^"]+)"`,
+					TokenHeader: "X-CSRF-Token",
+				},
+			},
+		},
+		{
+			name: "token from a cookie sent in the URL, with cookies kept across redirects",
+			website: Website{
+				BaseURL:   server.URL + "/profile/{}",
+				URLProbe:  server.URL + "/api/{}?token={token}",
+				ErrorType: "status_code",
+				ErrorCode: 404,
+				Bootstrap: &Bootstrap{
+					URL:         server.URL + "/home/{}",
+					TokenCookie: "xsrf",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.website.Name = "Session"
+			searcher := newTestSearcher(Data{}, Options{})
+			if result := searcher.SearchWebsite(context.Background(), tt.website, existingUser); result.Status != StatusFound {
+				t.Errorf("%s: got status %s (%s), want found", existingUser, result.Status, result.Error)
+			}
+			if result := searcher.SearchWebsite(context.Background(), tt.website, missingUser); result.Status != StatusNotFound {
+				t.Errorf("%s: got status %s (%s), want not found", missingUser, result.Status, result.Error)
+			}
+		})
+	}
+}
+
+func TestBootstrapErrors(t *testing.T) {
+	server, _ := newSessionServer(t)
+
+	tests := []struct {
+		name      string
+		bootstrap Bootstrap
+		want      string
+	}{
+		{"token missing from the body", Bootstrap{URL: server.URL + "/start/{}", TokenRegex: `name="api-key" content="([^"]+)"`}, "token not found"},
+		{"token cookie not set", Bootstrap{URL: server.URL + "/start/{}", TokenCookie: "api-key"}, `cookie "api-key" not set`},
+		{"bootstrap page unreachable", Bootstrap{URL: "http://127.0.0.1:1/start/{}"}, "error bootstrapping session"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			website := Website{Name: "Session", BaseURL: server.URL + "/profile/{}", ErrorType: "status_code", ErrorCode: 404, Bootstrap: &tt.bootstrap}
+			result := newTestSearcher(Data{}, Options{}).SearchWebsite(context.Background(), website, existingUser)
+			if result.Status != StatusError || !strings.Contains(result.Error, tt.want) {
+				t.Errorf("got status %s (%s), want an error containing %q", result.Status, result.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestBootstrapSessionsAreFresh(t *testing.T) {
+	// Every search bootstraps its own session, even with the response cache enabled
+	server, bootstraps := newSessionServer(t)
+	cache, err := NewCache(t.TempDir(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	website := Website{
+		Name:      "Session",
+		BaseURL:   server.URL + "/profile/{}",
+		ErrorType: "status_code",
+		ErrorCode: 404,
+		Bootstrap: &Bootstrap{URL: server.URL + "/start/{}", TokenRegex: `name="csrf" content="([^"]+)"`, TokenHeader: "X-CSRF-Token"},
+	}
+	searcher := newTestSearcher(Data{}, Options{Cache: cache})
+	for range 2 {
+		if result := searcher.SearchWebsite(context.Background(), website, existingUser); result.Status != StatusFound {
+			t.Errorf("got status %s (%s), want found", result.Status, result.Error)
+		}
+	}
+	if got := bootstraps.Load(); got != 2 {
+		t.Errorf("got %d bootstrap requests, want one per search", got)
+	}
+}
+
+func TestInsertToken(t *testing.T) {
+	got := insertToken("https://example.com/api?user=alice&token={token}", &session{token: "a b&c"})
+	if want := "https://example.com/api?user=alice&token=a+b%26c"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,143 @@
+package gosearch
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// UpstreamCatalogKey is the minisign public key the upstream catalog at DataURL is signed with.
+const UpstreamCatalogKey = "RWTA3mNlL2iU9WqICPeWNX3uuUmF9lwVqXJZi0WcPp7okzv3CzsLOTgX"
+
+// SignatureSuffix is appended to a remote catalog's URL to fetch its detached minisign signature.
+const SignatureSuffix = ".minisig"
+
+// ErrUnsigned is returned for remote catalogs without a signature from a trusted key.
+var ErrUnsigned = errors.New("catalog is not signed by a trusted key")
+
+// PublicKey is a minisign Ed25519 public key.
+type PublicKey struct {
+	ID  [8]byte           // Key ID, matched against the key ID in signatures
+	Key ed25519.PublicKey // Ed25519 public key
+}
+
+// CatalogTrust controls how remote catalogs are verified before they are loaded.
+type CatalogTrust struct {
+	Keys          []PublicKey // Keys remote catalogs may be signed with
+	AllowUnsigned bool        // Load remote catalogs that are unsigned or signed by an unknown key
+}
+
+// DefaultCatalogTrust returns the trust for loading catalogs with only UpstreamCatalogKey trusted.
+func DefaultCatalogTrust() CatalogTrust {
+	key, err := ParsePublicKey(UpstreamCatalogKey)
+	if err != nil {
+		panic("invalid UpstreamCatalogKey: " + err.Error())
+	}
+	return CatalogTrust{Keys: []PublicKey{key}}
+}
+
+// ParsePublicKey parses a minisign public key, either the base64 key itself or the contents of a .pub file.
+func ParsePublicKey(s string) (PublicKey, error) {
+	encoded := strings.TrimSpace(s)
+	if _, rest, ok := strings.Cut(encoded, "\n"); ok && strings.HasPrefix(encoded, "untrusted comment:") {
+		encoded = strings.TrimSpace(rest)
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != 42 || string(raw[:2]) != "Ed" {
+		return PublicKey{}, errors.New("invalid minisign public key")
+	}
+	var key PublicKey
+	copy(key.ID[:], raw[2:10])
+	key.Key = ed25519.PublicKey(raw[10:])
+	return key, nil
+}
+
+// LoadPublicKey parses a minisign public key given directly or as the path of a .pub file.
+func LoadPublicKey(value string) (PublicKey, error) {
+	if key, err := ParsePublicKey(value); err == nil {
+		return key, nil
+	}
+	content, err := os.ReadFile(value)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("%q is neither a minisign public key nor a readable key file", value)
+	}
+	return ParsePublicKey(string(content))
+}
+
+// verifyCatalog fetches the detached signature of the catalog downloaded from catalogURL and verifies content
+// against it. A catalog whose signature cannot be fetched or is from an unknown key is only accepted when trust
+// allows unsigned catalogs; a signature from a trusted key that does not match is never accepted.
+func verifyCatalog(client *http.Client, catalogURL string, content []byte, trust CatalogTrust) error {
+	signatureURL, err := url.Parse(catalogURL)
+	if err != nil {
+		return err
+	}
+	signatureURL.Path += SignatureSuffix
+	signatureURL.RawPath = ""
+
+	signature, err := download(client, signatureURL.String())
+	if err == nil {
+		err = VerifySignature(content, signature, trust.Keys)
+	} else {
+		err = fmt.Errorf("%w (%v)", ErrUnsigned, err)
+	}
+	if errors.Is(err, ErrUnsigned) && trust.AllowUnsigned {
+		return nil
+	}
+	return err
+}
+
+// VerifySignature verifies a detached minisign signature of content against the keys. It returns ErrUnsigned if
+// no key has the signature's key ID, and an error describing the problem if the signature does not match.
+func VerifySignature(content, signature []byte, keys []PublicKey) error {
+	// A signature file has four lines: an untrusted comment, the signature, a trusted comment and the global signature
+	lines := strings.Split(strings.TrimSpace(strings.ReplaceAll(string(signature), "\r\n", "\n")), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return errors.New("malformed minisign signature")
+	}
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sig) != 74 {
+		return errors.New("malformed minisign signature")
+	}
+	global, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(global) != ed25519.SignatureSize {
+		return errors.New("malformed minisign signature")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+
+	var key *PublicKey
+	for i := range keys {
+		if bytes.Equal(keys[i].ID[:], sig[2:10]) {
+			key = &keys[i]
+		}
+	}
+	if key == nil {
+		return ErrUnsigned
+	}
+
+	// "ED" signatures are of the BLAKE2b-512 hash of the content, legacy "Ed" signatures of the content itself
+	message := content
+	switch string(sig[:2]) {
+	case "ED":
+		hash := blake2b.Sum512(content)
+		message = hash[:]
+	case "Ed":
+	default:
+		return errors.New("unsupported minisign signature algorithm")
+	}
+	if !ed25519.Verify(key.Key, message, sig[10:]) {
+		return errors.New("signature does not match the catalog")
+	}
+	if !ed25519.Verify(key.Key, append(sig[10:len(sig):len(sig)], trustedComment...), global) {
+		return errors.New("signature's trusted comment has been altered")
+	}
+	return nil
+}
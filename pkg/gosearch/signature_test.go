@@ -0,0 +1,163 @@
+package gosearch
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// testKey is a minisign key pair for signing test catalogs.
+type testKey struct {
+	public  PublicKey
+	private ed25519.PrivateKey
+}
+
+// newTestKey generates a minisign key pair with a random key ID.
+func newTestKey(t *testing.T) testKey {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := testKey{public: PublicKey{Key: public}, private: private}
+	rand.Read(key.public.ID[:])
+	return key
+}
+
+// encode returns the key's public key as minisign prints it.
+func (k testKey) encode() string {
+	return base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), k.public.ID[:]...), k.public.Key...))
+}
+
+// sign returns a minisign signature file for content, prehashed with BLAKE2b-512 unless legacy is set.
+func (k testKey) sign(content []byte, legacy bool) []byte {
+	algorithm, message := "ED", content
+	if legacy {
+		algorithm = "Ed"
+	} else {
+		hash := blake2b.Sum512(content)
+		message = hash[:]
+	}
+	signature := ed25519.Sign(k.private, message)
+	trustedComment := "timestamp:1760000000\tfile:data.json\thashed"
+	global := ed25519.Sign(k.private, append(signature[:len(signature):len(signature)], trustedComment...))
+
+	encoded := base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), k.public.ID[:]...), signature...))
+	return []byte("untrusted comment: signature from minisign secret key\n" + encoded + "\ntrusted comment: " + trustedComment + "\n" + base64.StdEncoding.EncodeToString(global) + "\n")
+}
+
+func TestParsePublicKey(t *testing.T) {
+	key := newTestKey(t)
+	for _, s := range []string{key.encode(), "untrusted comment: minisign public key\n" + key.encode() + "\n"} {
+		parsed, err := ParsePublicKey(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.ID != key.public.ID || !parsed.Key.Equal(key.public.Key) {
+			t.Errorf("parsed a different key from %q", s)
+		}
+	}
+	if _, err := ParsePublicKey("not a key"); err == nil {
+		t.Error("invalid key: expected an error")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	key, other := newTestKey(t), newTestKey(t)
+	content := []byte(`{"websites": []}`)
+	keys := []PublicKey{key.public}
+
+	if err := VerifySignature(content, key.sign(content, false), keys); err != nil {
+		t.Errorf("prehashed signature: %v", err)
+	}
+	if err := VerifySignature(content, key.sign(content, true), keys); err != nil {
+		t.Errorf("legacy signature: %v", err)
+	}
+	if err := VerifySignature([]byte(`{"websites": [{}]}`), key.sign(content, false), keys); err == nil || errors.Is(err, ErrUnsigned) {
+		t.Errorf("tampered catalog: got %v, want a mismatch", err)
+	}
+	if err := VerifySignature(content, other.sign(content, false), keys); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("unknown key: got %v, want ErrUnsigned", err)
+	}
+
+	altered := bytes.Replace(key.sign(content, false), []byte("timestamp:1760000000"), []byte("timestamp:1760000001"), 1)
+	if err := VerifySignature(content, altered, keys); err == nil {
+		t.Error("altered trusted comment: expected an error")
+	}
+}
+
+func TestFetchSignedCatalog(t *testing.T) {
+	key := newTestKey(t)
+	catalog := []byte(`{"websites": [{"name": "A", "base_url": "https://a.example/{}", "errorType": "status_code"}]}`)
+	tampered := []byte(`{"websites": [{"name": "A", "base_url": "https://evil.example/{}", "errorType": "status_code"}]}`)
+
+	mux := http.NewServeMux()
+	serve := func(path string, body []byte) {
+		mux.HandleFunc("GET "+path, func(w http.ResponseWriter, r *http.Request) { w.Write(body) })
+	}
+	serve("/signed.json", catalog)
+	serve("/signed.json.minisig", key.sign(catalog, false))
+	serve("/unsigned.json", catalog)
+	serve("/tampered.json", tampered)
+	serve("/tampered.json.minisig", key.sign(catalog, false))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	trusted := CatalogTrust{Keys: []PublicKey{key.public}}
+	allowed := CatalogTrust{Keys: []PublicKey{key.public}, AllowUnsigned: true}
+	tests := []struct {
+		path  string
+		trust CatalogTrust
+		ok    bool
+	}{
+		{"/signed.json", trusted, true},
+		{"/signed.json?ref=main", trusted, true},
+		{"/signed.json", CatalogTrust{}, false},
+		{"/unsigned.json", trusted, false},
+		{"/unsigned.json", allowed, true},
+		{"/tampered.json", trusted, false},
+		{"/tampered.json", allowed, false},
+	}
+	for _, tt := range tests {
+		_, err := LoadCatalog(server.Client(), server.URL+tt.path, tt.trust)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s with %+v: got error %v", tt.path, tt.trust.AllowUnsigned, err)
+		}
+	}
+}
+
+// catalogTransport serves the upstream catalog at DataURL without a signature.
+type catalogTransport []byte
+
+func (c catalogTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	if req.URL.String() == DataURL {
+		recorder.Write(c)
+	} else {
+		http.NotFound(recorder, req)
+	}
+	return recorder.Result(), nil
+}
+
+func TestFetchUnsignedUpstream(t *testing.T) {
+	client := &http.Client{Transport: catalogTransport(`{"websites": [{"name": "A", "base_url": "https://a.example/{}", "errorType": "status_code"}]}`)}
+
+	// An unsigned upstream catalog is refused unless unsigned catalogs are explicitly allowed
+	trust := DefaultCatalogTrust()
+	if _, err := FetchData(client, trust); !errors.Is(err, ErrUnsigned) {
+		t.Errorf("got error %v, want ErrUnsigned", err)
+	}
+
+	trust.AllowUnsigned = true
+	data, err := FetchData(client, trust)
+	if err != nil || data.Name != UpstreamCatalog || len(data.Websites) != 1 {
+		t.Errorf("allowing unsigned catalogs: got %+v, %v, want the upstream catalog", data, err)
+	}
+}
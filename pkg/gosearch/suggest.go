@@ -0,0 +1,294 @@
+package gosearch
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+)
+
+// DefaultSuggestSamples is how many random usernames Suggest compares the existing username with by default.
+const DefaultSuggestSamples = 3
+
+// notFoundPhrases are phrases typical of pages for missing profiles, preferred when choosing an errorMsg.
+var notFoundPhrases = []string{"not found", "not exist", "doesn't exist", "does not exist", "no such", "unavailable", "404", "could not be found", "couldn't find", "isn't available"}
+
+// Suggestion is a catalog entry proposed by Suggest, with the evidence it was chosen on.
+type Suggestion struct {
+	Website  Website // Proposed catalog entry
+	Reason   string  // Why the error type was chosen
+	Verified bool    // Whether searching with the entry found the existing username and not a fresh random one
+}
+
+// suggestSample holds the responses for one username, without and with following redirects.
+type suggestSample struct {
+	username string
+	direct   ProbeResponse
+	followed ProbeResponse
+}
+
+// response returns the sample's response with or without following redirects.
+func (s suggestSample) response(follow bool) ProbeResponse {
+	if follow {
+		return s.followed
+	}
+	return s.direct
+}
+
+// RandomUsername returns a random lowercase username that is very unlikely to exist.
+func RandomUsername() string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	name := make([]byte, 14)
+	for i := range name {
+		name[i] = letters[rand.IntN(len(letters))]
+	}
+	return string(name)
+}
+
+// Suggest works out how to detect profiles on a website. It probes the website for an existing username and for
+// random usernames, with and without following redirects, and compares status codes, final URLs and bodies.
+// The website supplies the URL template and any cookies or settings needed to reach it; the returned
+// suggestion copies it with the most reliable error type filled in, verified against a fresh random username.
+func (s *Searcher) Suggest(ctx context.Context, website Website, existing string, samples int) (Suggestion, error) {
+	if samples <= 0 {
+		samples = DefaultSuggestSamples
+	}
+
+	probe := func(username string) (suggestSample, error) {
+		sample := suggestSample{username: username}
+		direct, followed := website, website
+		direct.FollowRedirects, followed.FollowRedirects = false, true
+
+		var err error
+		if sample.direct, err = s.Probe(ctx, direct, username, true); err != nil {
+			return sample, fmt.Errorf("error probing %s: %w", username, err)
+		}
+		if sample.followed, err = s.Probe(ctx, followed, username, true); err != nil {
+			return sample, fmt.Errorf("error probing %s: %w", username, err)
+		}
+		return sample, nil
+	}
+
+	// Probe the existing username twice so content that changes between requests is not mistaken for a profile marker
+	var found, missing []suggestSample
+	for range 2 {
+		sample, err := probe(existing)
+		if err != nil {
+			return Suggestion{}, err
+		}
+		found = append(found, sample)
+	}
+	for range samples {
+		sample, err := probe(RandomUsername())
+		if err != nil {
+			return Suggestion{}, err
+		}
+		missing = append(missing, sample)
+	}
+
+	suggestion := suggestRule(website, found, missing)
+	if suggestion.Website.ErrorType == "unknown" {
+		return suggestion, nil
+	}
+
+	// Check the rule the way a search would, against a username it was not derived from
+	suggestion.Verified = s.Verify(ctx, suggestion.Website, existing, RandomUsername()) == nil
+	return suggestion, nil
+}
+
+// suggestRule chooses the most reliable error type distinguishing the found samples from the missing ones.
+// Status codes are preferred over final URLs, which are preferred over body content.
+func suggestRule(website Website, found, missing []suggestSample) Suggestion {
+	modes := []bool{false, true}
+
+	// A status code every missing profile shares and no existing profile has
+	for _, follow := range modes {
+		if code, ok := sharedStatus(missing, follow); ok && usable(found, follow) && !answered(found, follow, code) {
+			website.FollowRedirects = follow
+			website.ErrorType = "status_code"
+			website.ErrorCode = code
+			return Suggestion{Website: website, Reason: fmt.Sprintf("existing profiles answer %d and missing profiles answer %d", found[0].response(follow).StatusCode, code)}
+		}
+	}
+
+	// A page every missing profile is redirected to
+	if usable(found, true) {
+		if url, ok := sharedFinalURL(missing); ok && templateURL(found[0].followed.FinalURL, found[0].username) != url {
+			website.FollowRedirects = true
+			website.ErrorType = "response_url"
+			website.ResponseURL = url
+			return Suggestion{Website: website, Reason: "missing profiles are redirected to " + url}
+		}
+	}
+
+	// Text every missing profile's page contains and no existing profile's page does
+	for _, follow := range modes {
+		if !usable(found, follow) {
+			continue
+		}
+		if text, ok := distinctText(missing, found, follow); ok {
+			website.FollowRedirects = follow
+			website.ErrorType = "errorMsg"
+			website.ErrorMsg = text
+			return Suggestion{Website: website, Reason: fmt.Sprintf("only missing profiles' pages contain %q", text)}
+		}
+	}
+
+	// Text every existing profile's page contains and no missing profile's page does
+	for _, follow := range modes {
+		if !usable(found, follow) {
+			continue
+		}
+		if text, ok := distinctText(found, missing, follow); ok {
+			website.FollowRedirects = follow
+			website.ErrorType = "profilePresence"
+			website.ErrorMsg = text
+			return Suggestion{Website: website, Reason: fmt.Sprintf("only existing profiles' pages contain %q", text)}
+		}
+	}
+
+	website.ErrorType = "unknown"
+	return Suggestion{Website: website, Reason: "no difference between existing and missing profiles was found"}
+}
+
+// usable reports whether every found sample answered below 400, since searches treat 4xx and 5xx as missing.
+func usable(found []suggestSample, follow bool) bool {
+	for _, sample := range found {
+		if sample.response(follow).StatusCode >= 400 {
+			return false
+		}
+	}
+	return true
+}
+
+// sharedStatus returns the status code every sample answered with, if they all agree.
+// Rate limiting and server errors are not reliable signs of a missing profile, so they are never shared.
+func sharedStatus(samples []suggestSample, follow bool) (int, bool) {
+	code := samples[0].response(follow).StatusCode
+	for _, sample := range samples[1:] {
+		if sample.response(follow).StatusCode != code {
+			return 0, false
+		}
+	}
+	return code, code != 429 && code < 500
+}
+
+// answered reports whether any sample answered with the status code.
+func answered(samples []suggestSample, follow bool, code int) bool {
+	for _, sample := range samples {
+		if sample.response(follow).StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+// templateURL replaces the first occurrence of username in a URL with the {} placeholder.
+func templateURL(url, username string) string {
+	return strings.Replace(url, username, "{}", 1)
+}
+
+// sharedFinalURL returns the final URL every sample was redirected to, with the username as {}, if they all agree.
+func sharedFinalURL(samples []suggestSample) (string, bool) {
+	url := templateURL(samples[0].followed.FinalURL, samples[0].username)
+	for _, sample := range samples[1:] {
+		if templateURL(sample.followed.FinalURL, sample.username) != url {
+			return "", false
+		}
+	}
+	return url, url != ""
+}
+
+// distinctText returns a fragment of page text every sample in with contains and no sample in without contains.
+// Fragments containing a username are skipped, since an error message must be the same for every username.
+func distinctText(with, without []suggestSample, follow bool) (string, bool) {
+	var usernames []string
+	for _, sample := range append(with[:len(with):len(with)], without...) {
+		usernames = append(usernames, sample.username)
+	}
+
+	var candidates []string
+	for _, fragment := range pageFragments(with[0].response(follow).Body) {
+		if !fragmentUsable(fragment, usernames) {
+			continue
+		}
+		shared := true
+		for _, sample := range with[1:] {
+			shared = shared && bytes.Contains(sample.response(follow).Body, []byte(fragment))
+		}
+		for _, sample := range without {
+			shared = shared && !bytes.Contains(sample.response(follow).Body, []byte(fragment))
+		}
+		if shared {
+			candidates = append(candidates, fragment)
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	// Prefer wording typical of error pages and titles, then the shortest fragment
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := fragmentRank(candidates[i]), fragmentRank(candidates[j])
+		if a != b {
+			return a < b
+		}
+		return len(candidates[i]) < len(candidates[j])
+	})
+	return candidates[0], true
+}
+
+// pageFragments splits a page into trimmed fragments at line breaks and before each tag,
+// so minified pages still yield short fragments. Every fragment is a substring of the page.
+func pageFragments(body []byte) []string {
+	var fragments []string
+	seen := map[string]bool{}
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		for len(line) > 0 {
+			end := bytes.IndexByte(line[1:], '<') + 1
+			if end == 0 {
+				end = len(line)
+			}
+			fragment := string(bytes.TrimSpace(line[:end]))
+			line = line[end:]
+			if fragment != "" && !seen[fragment] {
+				seen[fragment] = true
+				fragments = append(fragments, fragment)
+			}
+		}
+	}
+	return fragments
+}
+
+// fragmentUsable reports whether a fragment is a reasonable error message: short, containing text and no username.
+func fragmentUsable(fragment string, usernames []string) bool {
+	lower := strings.ToLower(fragment)
+	if len(fragment) < 8 || len(fragment) > 150 || !strings.ContainsAny(lower, "abcdefghijklmnopqrstuvwxyz") {
+		return false
+	}
+	for _, username := range usernames {
+		if strings.Contains(lower, strings.ToLower(username)) {
+			return false
+		}
+	}
+	return true
+}
+
+// fragmentRank orders fragments by how likely they are to be a stable marker: lower is better.
+func fragmentRank(fragment string) int {
+	lower := strings.ToLower(fragment)
+	for _, phrase := range notFoundPhrases {
+		if strings.Contains(lower, phrase) {
+			return 0
+		}
+	}
+	if strings.HasPrefix(lower, "<title") {
+		return 1
+	}
+	if !strings.HasPrefix(lower, "<") {
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,68 @@
+package gosearch
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSuggest(t *testing.T) {
+	server := newFixtureServer(t)
+	searcher := newTestSearcher(Data{}, Options{})
+
+	tests := []struct {
+		path string
+		want Website
+	}{
+		{"/status/{}", Website{ErrorType: "status_code", ErrorCode: 404}},
+		{"/code/{}", Website{ErrorType: "status_code", ErrorCode: 302}},
+		{"/profiles/{}", Website{ErrorType: "response_url", FollowRedirects: true, ResponseURL: server.URL + "/search?q={}"}},
+		{"/message/{}", Website{ErrorType: "errorMsg", ErrorMsg: "<h1>User not found"}},
+		{"/compressed/br/{}", Website{ErrorType: "errorMsg", ErrorMsg: "<h1>User not found"}},
+		{"/presence/{}", Website{ErrorType: "errorMsg", ErrorMsg: `<div class="search">Search`}},
+		{"/always-redirect/{}", Website{ErrorType: "status_code", FollowRedirects: true, ErrorCode: 404}},
+	}
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			website := Website{Name: "Fixture", BaseURL: server.URL + test.path}
+			suggestion, err := searcher.Suggest(context.Background(), website, existingUser, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := suggestion.Website
+			if got.ErrorType != test.want.ErrorType || got.ErrorCode != test.want.ErrorCode || got.ErrorMsg != test.want.ErrorMsg ||
+				got.ResponseURL != test.want.ResponseURL || got.FollowRedirects != test.want.FollowRedirects {
+				t.Errorf("got %+v (%s), want %+v", got, suggestion.Reason, test.want)
+			}
+			if !suggestion.Verified {
+				t.Errorf("suggestion not verified: %+v", got)
+			}
+		})
+	}
+}
+
+func TestSuggestUnknown(t *testing.T) {
+	server := newFixtureServer(t)
+	website := Website{Name: "Fixture", BaseURL: server.URL + "/search?q={}"}
+
+	suggestion, err := newTestSearcher(Data{}, Options{}).Suggest(context.Background(), website, existingUser, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suggestion.Website.ErrorType != "unknown" || suggestion.Verified {
+		t.Errorf("got %+v, verified %t; want unknown", suggestion.Website, suggestion.Verified)
+	}
+}
+
+func TestPageFragments(t *testing.T) {
+	got := pageFragments([]byte("<html><head><title>Example</title></head>\n  <p>Hello</p>"))
+	want := []string{"<html>", "<head>", "<title>Example", "</title>", "</head>", "<p>Hello", "</p>"}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("fragment %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
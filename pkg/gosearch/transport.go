@@ -0,0 +1,174 @@
+package gosearch
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Cipher sets a website can select in its TLS settings.
+const (
+	CiphersDefault = "default" // GoSearch's ECDHE AEAD cipher suites
+	CiphersGo      = "go"      // Go's built-in cipher suite defaults
+	CiphersLegacy  = "legacy"  // Default suites plus CBC and RSA key exchange suites for old servers
+)
+
+// defaultCipherSuites are the cipher suites offered unless a website selects another cipher set.
+var defaultCipherSuites = []uint16{
+	tls.TLS_AES_128_GCM_SHA256,
+	tls.TLS_AES_256_GCM_SHA384,
+	tls.TLS_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// legacyCipherSuites extends the default cipher suites for servers that do not support them.
+var legacyCipherSuites = append(slices.Clone(defaultCipherSuites),
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_CBC_SHA,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+)
+
+// tlsVersions maps the TLS versions a website can require to their identifiers.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSSettings overrides the TLS and protocol settings used for a website.
+type TLSSettings struct {
+	MinVersion string `json:"min_version,omitempty"` // Minimum TLS version: 1.0, 1.1, 1.2 or 1.3
+	Ciphers    string `json:"ciphers,omitempty"`     // Cipher set: default, go or legacy
+	HTTP2      *bool  `json:"http2,omitempty"`       // Whether to negotiate HTTP/2, overriding Options.HTTP2
+}
+
+// NewTransport creates an HTTP transport with GoSearch's TLS settings that sends requests through proxy.
+// A nil proxy uses the environment's proxy settings.
+func NewTransport(proxy *url.URL) *http.Transport {
+	return &http.Transport{
+		// TLS configuration for secure HTTP requests
+		TLSClientConfig: &tls.Config{
+			MinVersion:       tls.VersionTLS12,                                        // Minimum TLS version
+			CipherSuites:     defaultCipherSuites,                                     // Supported cipher suites
+			CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384}, // Preferred elliptic curves
+			NextProtos:       []string{"http/1.1"},                                    // Supported protocols
+		},
+		Proxy: proxyFunc(proxy),
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// configureTLS applies a website's TLS settings and the Searcher's protocol options to a transport created by NewTransport.
+func configureTLS(transport *http.Transport, settings TLSSettings, http2, insecure bool) error {
+	config := transport.TLSClientConfig
+
+	if settings.MinVersion != "" {
+		version, ok := tlsVersions[settings.MinVersion]
+		if !ok {
+			return fmt.Errorf("unknown TLS version %q (expected 1.0, 1.1, 1.2 or 1.3)", settings.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	switch settings.Ciphers {
+	case "", CiphersDefault:
+	case CiphersGo:
+		config.CipherSuites = nil
+	case CiphersLegacy:
+		config.CipherSuites = legacyCipherSuites
+	default:
+		return fmt.Errorf("unknown cipher set %q (expected default, go or legacy)", settings.Ciphers)
+	}
+
+	// Offer h2 alongside http/1.1 and let the server choose
+	if settings.HTTP2 != nil {
+		http2 = *settings.HTTP2
+	}
+	if http2 {
+		config.NextProtos = nil
+		transport.ForceAttemptHTTP2 = true
+	}
+
+	config.InsecureSkipVerify = insecure
+	return nil
+}
+
+// newTransport creates a transport through proxy using the Searcher's protocol options and the given TLS settings.
+func (s *Searcher) newTransport(proxy *url.URL, settings TLSSettings) (*http.Transport, error) {
+	transport := NewTransport(proxy)
+	if err := configureTLS(transport, settings, s.options.HTTP2, s.options.Insecure); err != nil {
+		return nil, err
+	}
+	return transport, nil
+}
+
+// transportFor returns the transport for requests to the website, honouring its proxy and TLS settings.
+func (s *Searcher) transportFor(website Website) (http.RoundTripper, error) {
+	if website.Proxy == "" && website.TLS == nil {
+		return s.transport, nil
+	}
+
+	var settings TLSSettings
+	if website.TLS != nil {
+		settings = *website.TLS
+	}
+	http2 := ""
+	if settings.HTTP2 != nil {
+		http2 = strconv.FormatBool(*settings.HTTP2)
+	}
+	key := website.Proxy + "|" + settings.MinVersion + "|" + settings.Ciphers + "|" + http2
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Reuse one transport per combination of settings so connections are pooled
+	if transport, ok := s.siteTransports[key]; ok {
+		return s.wrap(transport), nil
+	}
+
+	// Use the website's own proxy, or the Searcher's proxies
+	proxy := s.options.Proxy
+	if website.Proxy != "" {
+		var err error
+		if proxy, err = ParseProxy(website.Proxy); err != nil {
+			return nil, err
+		}
+	} else if s.options.ProxyPool != nil {
+		proxy = nil
+	}
+
+	base, err := s.newTransport(proxy, settings)
+	if err != nil {
+		return nil, err
+	}
+	var transport http.RoundTripper = base
+	if website.Proxy == "" && s.options.ProxyPool != nil {
+		transport = s.options.ProxyPool.Transport(base)
+	}
+
+	s.siteTransports[key] = transport
+	return s.wrap(transport), nil
+}
@@ -0,0 +1,176 @@
+package gosearch
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestConfigureTLS(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name     string
+		settings TLSSettings
+		http2    bool
+		insecure bool
+		check    func(t *testing.T, transport *http.Transport)
+		err      string
+	}{
+		{"defaults", TLSSettings{}, false, false, func(t *testing.T, transport *http.Transport) {
+			config := transport.TLSClientConfig
+			if config.MinVersion != tls.VersionTLS12 || len(config.CipherSuites) != len(defaultCipherSuites) || config.InsecureSkipVerify {
+				t.Errorf("got min version %x, %d cipher suites, insecure %v", config.MinVersion, len(config.CipherSuites), config.InsecureSkipVerify)
+			}
+			if len(config.NextProtos) != 1 || config.NextProtos[0] != "http/1.1" || transport.ForceAttemptHTTP2 {
+				t.Errorf("got protocols %q, want HTTP/1.1 only", config.NextProtos)
+			}
+		}, ""},
+		{"min version", TLSSettings{MinVersion: "1.3"}, false, false, func(t *testing.T, transport *http.Transport) {
+			if transport.TLSClientConfig.MinVersion != tls.VersionTLS13 {
+				t.Errorf("got min version %x", transport.TLSClientConfig.MinVersion)
+			}
+		}, ""},
+		{"old min version", TLSSettings{MinVersion: "1.0"}, false, false, func(t *testing.T, transport *http.Transport) {
+			if transport.TLSClientConfig.MinVersion != tls.VersionTLS10 {
+				t.Errorf("got min version %x", transport.TLSClientConfig.MinVersion)
+			}
+		}, ""},
+		{"go ciphers", TLSSettings{Ciphers: CiphersGo}, false, false, func(t *testing.T, transport *http.Transport) {
+			if transport.TLSClientConfig.CipherSuites != nil {
+				t.Errorf("got %d cipher suites, want Go's defaults", len(transport.TLSClientConfig.CipherSuites))
+			}
+		}, ""},
+		{"legacy ciphers", TLSSettings{Ciphers: CiphersLegacy}, false, false, func(t *testing.T, transport *http.Transport) {
+			if len(transport.TLSClientConfig.CipherSuites) != len(legacyCipherSuites) {
+				t.Errorf("got %d cipher suites, want the legacy set", len(transport.TLSClientConfig.CipherSuites))
+			}
+		}, ""},
+		{"http2 option", TLSSettings{}, true, false, func(t *testing.T, transport *http.Transport) {
+			if transport.TLSClientConfig.NextProtos != nil || !transport.ForceAttemptHTTP2 {
+				t.Errorf("got protocols %q, want HTTP/2 offered", transport.TLSClientConfig.NextProtos)
+			}
+		}, ""},
+		{"http2 by website", TLSSettings{HTTP2: &yes}, false, false, func(t *testing.T, transport *http.Transport) {
+			if !transport.ForceAttemptHTTP2 {
+				t.Error("want HTTP/2 offered")
+			}
+		}, ""},
+		{"http2 off by website", TLSSettings{HTTP2: &no}, true, false, func(t *testing.T, transport *http.Transport) {
+			if transport.ForceAttemptHTTP2 {
+				t.Error("want HTTP/1.1 only")
+			}
+		}, ""},
+		{"insecure", TLSSettings{}, false, true, func(t *testing.T, transport *http.Transport) {
+			if !transport.TLSClientConfig.InsecureSkipVerify {
+				t.Error("want certificate verification skipped")
+			}
+		}, ""},
+		{"unknown version", TLSSettings{MinVersion: "1.4"}, false, false, nil, "unknown TLS version"},
+		{"unknown ciphers", TLSSettings{Ciphers: "export"}, false, false, nil, "unknown cipher set"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := NewTransport(nil)
+			err := configureTLS(transport, tt.settings, tt.http2, tt.insecure)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("got error %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, transport)
+		})
+	}
+
+	// Transports share the default cipher list, so configuring one must not change the others
+	configureTLS(NewTransport(nil), TLSSettings{Ciphers: CiphersLegacy}, false, false)
+	if suites := NewTransport(nil).TLSClientConfig.CipherSuites; len(suites) != len(defaultCipherSuites) {
+		t.Errorf("a new transport has %d cipher suites, want the defaults", len(suites))
+	}
+}
+
+// newTLSServer starts a TLS server answering with the protocol each request was made with.
+func newTLSServer(t *testing.T, http2 bool, config *tls.Config) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Proto)
+	}))
+	server.EnableHTTP2 = http2
+	server.TLS = config
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// probeProto probes the server as a website with the TLS settings and returns the protocol the request was made with.
+func probeProto(searcher *Searcher, server *httptest.Server, settings *TLSSettings) (string, error) {
+	website := Website{Name: "TLS", BaseURL: server.URL + "/{}", ErrorType: "status_code", TLS: settings}
+	response, err := searcher.Probe(context.Background(), website, existingUser, false)
+	if err != nil {
+		return "", err
+	}
+	return string(response.Body), nil
+}
+
+func TestTLSMinVersion(t *testing.T) {
+	// The server only speaks TLS 1.2, so a website requiring TLS 1.3 cannot be reached
+	server := newTLSServer(t, false, &tls.Config{MaxVersion: tls.VersionTLS12})
+	searcher := newTestSearcher(Data{}, Options{Insecure: true})
+
+	if _, err := probeProto(searcher, server, nil); err != nil {
+		t.Errorf("got error %v, want the website reached over TLS 1.2", err)
+	}
+	if _, err := probeProto(searcher, server, &TLSSettings{MinVersion: "1.3"}); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("got error %v, want a protocol version error", err)
+	}
+	if _, err := probeProto(searcher, server, &TLSSettings{MinVersion: "2.0"}); err == nil || !strings.Contains(err.Error(), "unknown TLS version") {
+		t.Errorf("got error %v, want the unknown version reported", err)
+	}
+}
+
+func TestInsecure(t *testing.T) {
+	// httptest's certificate is self-signed, so it is only accepted with Insecure
+	server := newTLSServer(t, false, nil)
+	if _, err := probeProto(newTestSearcher(Data{}, Options{}), server, nil); err == nil || !strings.Contains(err.Error(), "certificate") {
+		t.Errorf("got error %v, want a certificate error", err)
+	}
+	if _, err := probeProto(newTestSearcher(Data{}, Options{Insecure: true}), server, nil); err != nil {
+		t.Errorf("got error %v, want the website reached with Insecure", err)
+	}
+}
+
+func TestHTTP2(t *testing.T) {
+	server := newTLSServer(t, true, nil)
+	yes, no := true, false
+
+	tests := []struct {
+		name     string
+		http2    bool
+		settings *TLSSettings
+		want     string
+	}{
+		{"default", false, nil, "HTTP/1.1"},
+		{"option", true, nil, "HTTP/2.0"},
+		{"enabled by website", false, &TLSSettings{HTTP2: &yes}, "HTTP/2.0"},
+		{"disabled by website", true, &TLSSettings{HTTP2: &no}, "HTTP/1.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searcher := newTestSearcher(Data{}, Options{HTTP2: tt.http2, Insecure: true})
+			proto, err := probeProto(searcher, server, tt.settings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proto != tt.want {
+				t.Errorf("got %s, want %s", proto, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// maxDiffLines is how many differing body lines are printed for each username.
+const maxDiffLines = 15
+
+// cookieFlag collects repeated --cookie flags.
+type cookieFlag []gosearch.Cookie
+
+// String returns the configured cookies.
+func (c *cookieFlag) String() string {
+	cookies := make([]string, len(*c))
+	for i, cookie := range *c {
+		cookies[i] = cookie.Name + "=" + cookie.Value
+	}
+	return strings.Join(cookies, ",")
+}
+
+// Set parses and appends a name=value cookie.
+func (c *cookieFlag) Set(value string) error {
+	name, val, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid cookie %q (expected name=value)", value)
+	}
+	*c = append(*c, gosearch.Cookie{Name: name, Value: val})
+	return nil
+}
+
+// Probe requests a URL the way a search would and prints the response, handling the arguments of the probe subcommand.
+// With both --exists and --missing it probes both usernames and prints how the responses differ.
+func Probe(args []string) {
+	flags := flag.NewFlagSet("probe", flag.ExitOnError)
+	followRedirects := flags.Bool("follow-redirects", false, "Follow redirects, like a website with follow_redirects set")
+	compressed := flags.Bool("compressed", true, "Ask for a gzip, deflate or br body and decompress it; false asks for an uncompressed body")
+	bodyFile := flags.String("body", "", "File to save the response body to; {} is replaced with the username")
+	exists := flags.String("exists", "", "Username with a profile, replacing {} in the URL")
+	missing := flags.String("missing", "", "Username without a profile; with --exists, the two responses are compared")
+	contains := flags.String("contains", "", "Report whether the body contains this text, such as a candidate errorMsg")
+	siteName := flags.String("site", "", "Website name, used to apply its secrets (defaults to the URL's host)")
+	userAgent := flags.String("user-agent", "", "User-Agent to send, overriding the header profile's")
+	var cookies cookieFlag
+	flags.Var(&cookies, "cookie", "Cookie to send as name=value; may be repeated")
+	var netFlags networkFlags
+	netFlags.register(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: gosearch probe [flags] <url>")
+		flags.PrintDefaults()
+	}
+
+	positional := parseInterspersed(flags, args)
+	if len(positional) != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	template := positional[0]
+
+	parsed, err := url.Parse(strings.ReplaceAll(template, "{}", "username"))
+	if err != nil || parsed.Host == "" {
+		Red("Error: ", template, " is not an absolute URL").Println()
+		os.Exit(1)
+	}
+	if strings.Contains(template, "{}") && *exists == "" && *missing == "" {
+		Red("Error: the URL contains {}; pass the username to probe with --exists or --missing").Println()
+		os.Exit(1)
+	}
+
+	network, err := netFlags.parse()
+	if err != nil {
+		fmt.Printf("Error configuring network: %v\n", err)
+		os.Exit(1)
+	}
+	defer network.Close()
+
+	website := gosearch.Website{
+		Name:            *siteName,
+		BaseURL:         template,
+		FollowRedirects: *followRedirects,
+		UserAgent:       *userAgent,
+		Cookies:         cookies,
+	}
+	if website.Name == "" {
+		website.Name = parsed.Hostname()
+	}
+	searcher := gosearch.NewSearcher(gosearch.Data{Websites: []gosearch.Website{website}}, network.Options())
+
+	// Probe every username given, or the URL as is
+	var usernames []string
+	for _, username := range []string{*exists, *missing} {
+		if username != "" {
+			usernames = append(usernames, username)
+		}
+	}
+	if len(usernames) == 0 {
+		usernames = []string{""}
+	}
+
+	responses := make([]gosearch.ProbeResponse, len(usernames))
+	for i, username := range usernames {
+		if i > 0 {
+			fmt.Println()
+		}
+		Yellow("[*] Probing ", gosearch.BuildURL(template, username)).Println()
+
+		response, err := searcher.Probe(context.Background(), website, username, *compressed)
+		if err != nil {
+			Red("Error probing URL:").Print()
+			White(" " + err.Error()).Println()
+			os.Exit(1)
+		}
+		responses[i] = response
+		PrintProbeResponse(response, *contains)
+
+		if *bodyFile != "" {
+			path := probeBodyPath(*bodyFile, username, len(usernames) > 1)
+			if err := os.WriteFile(path, response.Body, 0o644); err != nil {
+				Red("Error saving response body:").Print()
+				White(" " + err.Error()).Println()
+				os.Exit(1)
+			}
+			Green("[+] Saved response body to ", path).Println()
+		}
+	}
+
+	if len(responses) == 2 {
+		fmt.Println()
+		PrintProbeDiff(usernames[0], responses[0], usernames[1], responses[1])
+	}
+}
+
+// probeBodyPath returns the file a probed body is saved to. When several usernames are probed and the path
+// has no {} placeholder, the username is added before the extension so the bodies do not overwrite each other.
+func probeBodyPath(path, username string, several bool) string {
+	if several && !strings.Contains(path, "{}") {
+		ext := filepath.Ext(path)
+		path = strings.TrimSuffix(path, ext) + "-{}" + ext
+	}
+	return strings.ReplaceAll(path, "{}", strings.NewReplacer("/", "_", `\`, "_").Replace(username))
+}
+
+// PrintProbeResponse prints a probe's status, final URL, headers and title, and whether its body contains text.
+func PrintProbeResponse(response gosearch.ProbeResponse, contains string) {
+	Green("[+] Status    : ", response.Status).Println()
+	Green("[+] Final URL : ", response.FinalURL).Println()
+	Green("[+] Title     : ", response.Title()).Println()
+	Green("[+] Body      : ", len(response.Body), " bytes").Println()
+	Green("[+] Headers   :").Println()
+
+	keys := make([]string, 0, len(response.Header))
+	for key := range response.Header {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, value := range response.Header[key] {
+			Gray("    ", key, ": ", value).Println()
+		}
+	}
+
+	if contains != "" {
+		if bytes.Contains(response.Body, []byte(contains)) {
+			Green("[+] Body contains ", fmt.Sprintf("%q", contains)).Println()
+		} else {
+			Red("[-] Body does not contain ", fmt.Sprintf("%q", contains)).Println()
+		}
+	}
+}
+
+// PrintProbeDiff prints how the responses for an existing and a missing username differ.
+// Each username is replaced with {} before comparing, so only differences caused by the profile remain.
+func PrintProbeDiff(exists string, found gosearch.ProbeResponse, missing string, notFound gosearch.ProbeResponse) {
+	Yellow("[*] Comparing ", exists, " (exists) with ", missing, " (missing)").Println()
+
+	compare := func(field, a, b string) {
+		if a == b {
+			Gray("[-] ", field, " is the same: ", a).Println()
+			return
+		}
+		Green("[+] ", field, " differs:").Println()
+		Green("    exists  : ", a).Println()
+		Red("    missing : ", b).Println()
+	}
+	compare("Status", found.Status, notFound.Status)
+	compare("Final URL", strings.ReplaceAll(found.FinalURL, exists, "{}"), strings.ReplaceAll(notFound.FinalURL, missing, "{}"))
+	compare("Title", strings.ReplaceAll(found.Title(), exists, "{}"), strings.ReplaceAll(notFound.Title(), missing, "{}"))
+
+	onlyFound, onlyNotFound := gosearch.DiffLines(
+		bytes.ReplaceAll(found.Body, []byte(exists), []byte("{}")),
+		bytes.ReplaceAll(notFound.Body, []byte(missing), []byte("{}")),
+	)
+	printLines := func(label string, lines []string, color func(args ...interface{}) Color) {
+		if len(lines) == 0 {
+			Gray("[-] No body lines only in the ", label, " response").Println()
+			return
+		}
+		color("[+] ", len(lines), " body lines only in the ", label, " response:").Println()
+		for i, line := range lines {
+			if i == maxDiffLines {
+				Gray("    ... ", len(lines)-maxDiffLines, " more").Println()
+				break
+			}
+			if len(line) > 200 {
+				line = line[:200] + "..."
+			}
+			color("    ", line).Println()
+		}
+	}
+	printLines("exists", onlyFound, Green)
+	printLines("missing", onlyNotFound, Red)
+}
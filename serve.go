@@ -0,0 +1,480 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// Job statuses reported by the API server.
+const (
+	JobQueued  = "queued"  // Waiting for a free job slot
+	JobRunning = "running" // Search in progress
+	JobDone    = "done"    // Search finished; report available
+)
+
+// jobRetention is how long finished jobs are kept before being pruned.
+const jobRetention = 24 * time.Hour
+
+// JobOptions holds the parameters of a search job submitted to the API server.
+type JobOptions struct {
+	Username              string   `json:"username"`                           // Username to search
+	NoFalsePositives      bool     `json:"no_false_positives"`                 // Skip websites that cannot be verified
+	BreachDirectoryAPIKey string   `json:"breach_directory_api_key,omitempty"` // Optional Breach Directory API key
+	Sites                 []string `json:"sites,omitempty"`                    // Only search these websites
+	ExcludeSites          []string `json:"exclude_sites,omitempty"`            // Never search these websites
+	Tags                  []string `json:"tags,omitempty"`                     // Only search websites with one of these categories or tags
+	ExcludeTags           []string `json:"exclude_tags,omitempty"`             // Never search websites with any of these categories or tags
+	IncludeNSFW           bool     `json:"include_nsfw,omitempty"`             // Also search websites marked nsfw, if the server allows it
+}
+
+// Filter returns the website filter requested by the job.
+func (o JobOptions) Filter() gosearch.Filter {
+	return gosearch.Filter{Sites: o.Sites, ExcludeSites: o.ExcludeSites, Tags: o.Tags, ExcludeTags: o.ExcludeTags, IncludeNSFW: o.IncludeNSFW}
+}
+
+// JobEvent represents a progress event streamed to Server-Sent Events subscribers.
+type JobEvent struct {
+	Type string      // Event type (stage, result, done)
+	Data interface{} // JSON-encoded event payload
+}
+
+// JobStatus is a snapshot of a job's progress.
+type JobStatus struct {
+	ID            string     `json:"id"`                 // Job ID
+	Username      string     `json:"username"`           // Searched username
+	Status        string     `json:"status"`             // queued, running or done
+	Stage         string     `json:"stage,omitempty"`    // Current stage
+	Websites      int        `json:"websites"`           // Number of websites to search
+	SitesChecked  int        `json:"sites_checked"`      // Number of websites searched so far
+	ProfilesFound int        `json:"profiles_found"`     // Number of profiles found so far
+	Created       time.Time  `json:"created"`            // Time the job was submitted
+	Finished      *time.Time `json:"finished,omitempty"` // Time the job finished
+	Warnings      []string   `json:"warnings,omitempty"` // Problems with the job's filter that did not stop it
+}
+
+// Job is a search submitted to the API server. It implements gosearch.Handler to track its own progress.
+type Job struct {
+	mu      sync.Mutex
+	status  JobStatus
+	options JobOptions
+	events  []JobEvent
+	changed chan struct{} // Closed and replaced whenever a new event is appended
+	report  *gosearch.Report
+}
+
+// NewJob creates a queued job for the given options.
+func NewJob(id string, options JobOptions, websites int) *Job {
+	return &Job{
+		status: JobStatus{
+			ID:       id,
+			Username: options.Username,
+			Status:   JobQueued,
+			Websites: websites,
+			Created:  time.Now(),
+		},
+		options: options,
+		changed: make(chan struct{}),
+	}
+}
+
+// Status returns a snapshot of the job's progress.
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// Report returns the job's final report, or nil if the job has not finished.
+func (j *Job) Report() *gosearch.Report {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.report
+}
+
+// Events returns the events from index onwards, a channel closed when more arrive, and whether the job is done.
+func (j *Job) Events(index int) ([]JobEvent, <-chan struct{}, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if index > len(j.events) {
+		index = len(j.events)
+	}
+	return j.events[index:], j.changed, j.status.Status == JobDone
+}
+
+// appendEvent records an event and wakes up subscribers. The caller must hold j.mu.
+func (j *Job) appendEvent(eventType string, data interface{}) {
+	j.events = append(j.events, JobEvent{Type: eventType, Data: data})
+	close(j.changed)
+	j.changed = make(chan struct{})
+}
+
+// StageStarted records the start of a search stage.
+func (j *Job) StageStarted(stage string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.Status = JobRunning
+	j.status.Stage = stage
+	j.appendEvent("stage", map[string]string{"stage": stage, "state": "started"})
+}
+
+// SiteResult records the result of searching a single website.
+func (j *Job) SiteResult(result gosearch.Result) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status.SitesChecked++
+	if result.Profile() {
+		j.status.ProfilesFound++
+	}
+	j.appendEvent("result", result)
+}
+
+// StageFinished records the completion of a search stage.
+func (j *Job) StageFinished(stage string, report *gosearch.Report, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	event := map[string]string{"stage": stage, "state": "finished"}
+	if err != nil {
+		event["error"] = err.Error()
+	}
+	j.appendEvent("stage", event)
+}
+
+// RunFinished stores the final report and marks the job as done.
+func (j *Job) RunFinished(report *gosearch.Report) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	final := *report
+	j.report = &final
+	j.status.Status = JobDone
+	j.status.Stage = ""
+	j.status.Finished = &now
+	j.appendEvent("done", j.status)
+}
+
+// Server exposes the search engine over a REST API.
+type Server struct {
+	data    gosearch.Data    // Website catalog
+	filter  gosearch.Filter  // Websites every job is limited to
+	token   string           // Optional bearer token required on every request
+	options gosearch.Options // Search options shared by every job
+	slots   chan struct{}    // Limits the number of concurrently running jobs
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+// NewServer creates an API server searching the websites of data that filter selects, running at most maxJobs jobs
+// at once. Every job starts from options, overridden by the job's own options, and may narrow the websites further.
+func NewServer(data gosearch.Data, filter gosearch.Filter, token string, maxJobs int, options gosearch.Options) *Server {
+	if maxJobs < 1 {
+		maxJobs = 1
+	}
+	return &Server{
+		data:    data,
+		filter:  filter,
+		token:   token,
+		options: options,
+		slots:   make(chan struct{}, maxJobs),
+		jobs:    make(map[string]*Job),
+	}
+}
+
+// Handler returns the HTTP handler serving the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/jobs", s.handleSubmit)
+	mux.HandleFunc("GET /api/jobs", s.handleList)
+	mux.HandleFunc("GET /api/jobs/{id}", s.handleStatus)
+	mux.HandleFunc("GET /api/jobs/{id}/events", s.handleEvents)
+	mux.HandleFunc("GET /api/jobs/{id}/report", s.handleReport)
+	return s.authenticate(mux)
+}
+
+// authenticate rejects requests without the configured bearer token.
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	if s.token == "" {
+		return next
+	}
+	expected := []byte("Bearer " + s.token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleSubmit creates a new search job from a JobOptions request body.
+func (s *Server) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 64<<10))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "error reading request body")
+		return
+	}
+
+	var options JobOptions
+	if err := sonic.Unmarshal(body, &options); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	options.Username = strings.TrimSpace(options.Username)
+	if options.Username == "" {
+		writeError(w, http.StatusBadRequest, "username is required")
+		return
+	}
+
+	if options.IncludeNSFW && !s.filter.IncludeNSFW {
+		writeError(w, http.StatusBadRequest, "nsfw websites are disabled on this server; start it with --include-nsfw to allow them")
+		return
+	}
+
+	// Check the job's filter against the websites this server searches, not the whole catalog
+	served := s.data.Filter(s.filter)
+	if err := served.CheckFilter(options.Filter()); err != nil {
+		writeError(w, http.StatusBadRequest, strings.ReplaceAll(err.Error(), "\n", "; "))
+		return
+	}
+	data := served.Filter(options.Filter())
+
+	job := NewJob(newJobID(), options, len(data.Websites))
+	job.status.Warnings = served.FilterWarnings(options.Filter())
+
+	s.mu.Lock()
+	s.pruneJobs()
+	s.jobs[job.status.ID] = job
+	s.mu.Unlock()
+
+	go s.run(job, data)
+
+	w.Header().Set("Location", "/api/jobs/"+job.status.ID)
+	writeJSON(w, http.StatusAccepted, job.Status())
+}
+
+// run waits for a free slot and runs the job against the given websites.
+func (s *Server) run(job *Job, data gosearch.Data) {
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	options := s.options
+	options.NoFalsePositives = job.options.NoFalsePositives
+	options.BreachDirectoryAPIKey = job.options.BreachDirectoryAPIKey
+	searcher := gosearch.NewSearcher(data, options)
+	searcher.Run(context.Background(), job.options.Username, job)
+}
+
+// handleList returns the status of every known job, newest first.
+func (s *Server) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	statuses := make([]JobStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		statuses = append(statuses, job.Status())
+	}
+	s.mu.Unlock()
+
+	sort.Slice(statuses, func(i, k int) bool {
+		return statuses[i].Created.After(statuses[k].Created)
+	})
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+// handleStatus returns the status of a single job.
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	job := s.job(w, r)
+	if job == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, job.Status())
+}
+
+// handleReport returns the final report of a finished job.
+func (s *Server) handleReport(w http.ResponseWriter, r *http.Request) {
+	job := s.job(w, r)
+	if job == nil {
+		return
+	}
+
+	report := job.Report()
+	if report == nil {
+		writeError(w, http.StatusConflict, "job has not finished")
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleEvents streams a job's progress as Server-Sent Events, replaying earlier events first.
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	job := s.job(w, r)
+	if job == nil {
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming not supported")
+		return
+	}
+
+	// Resume after the last event the client received, if any
+	index := 0
+	if id, err := strconv.Atoi(r.Header.Get("Last-Event-ID")); err == nil && id >= 0 {
+		index = id + 1
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		events, changed, done := job.Events(index)
+		for _, event := range events {
+			data, err := sonic.Marshal(event.Data)
+			if err != nil {
+				log.Printf("error encoding event: %v", err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", index, event.Type, data)
+			index++
+		}
+		flusher.Flush()
+
+		if done {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// job looks up the job named in the request path, writing a 404 if it does not exist.
+func (s *Server) job(w http.ResponseWriter, r *http.Request) *Job {
+	s.mu.Lock()
+	job := s.jobs[r.PathValue("id")]
+	s.mu.Unlock()
+
+	if job == nil {
+		writeError(w, http.StatusNotFound, "job not found")
+	}
+	return job
+}
+
+// pruneJobs removes jobs that finished more than jobRetention ago. The caller must hold s.mu.
+func (s *Server) pruneJobs() {
+	for id, job := range s.jobs {
+		status := job.Status()
+		if status.Finished != nil && time.Since(*status.Finished) > jobRetention {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+// newJobID returns a random job identifier.
+func newJobID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := sonic.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// Serve runs the HTTP API server, handling the arguments of the serve subcommand.
+func Serve(args []string) {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "127.0.0.1:8080", "Address to listen on")
+	token := flags.String("token", os.Getenv("GOSEARCH_API_TOKEN"), "Bearer token required by the API (defaults to $GOSEARCH_API_TOKEN)")
+	maxJobs := flags.Int("max-jobs", 4, "Maximum number of jobs to run at once")
+	var netFlags networkFlags
+	netFlags.register(flags)
+	var filters filterFlags
+	filters.register(flags)
+	var catalogs catalogFlags
+	catalogs.register(flags)
+	var domainOptions domainFlags
+	domainOptions.register(flags)
+	flags.Parse(args)
+
+	network, err := netFlags.parse()
+	if err != nil {
+		fmt.Printf("Error configuring network: %v\n", err)
+		os.Exit(1)
+	}
+	domains, err := domainOptions.parse()
+	if err != nil {
+		fmt.Printf("Error configuring domains: %v\n", err)
+		os.Exit(1)
+	}
+	options := network.Options()
+	domains.apply(&options)
+
+	// Load website data from JSON
+	data, _, err := catalogs.load(network.Transport)
+	if err != nil {
+		fmt.Printf("Error loading catalogs: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Jobs can only narrow down the websites chosen here
+	filter := filters.filter()
+	if err := data.CheckFilter(filter); err != nil {
+		fmt.Printf("Error filtering websites: %v\n", err)
+		os.Exit(1)
+	}
+	for _, warning := range data.FilterWarnings(filter) {
+		Yellow("[!] " + warning).Println()
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           NewServer(data, filter, *token, *maxJobs, options).Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	Yellowf("[*] GoSearch API listening on %s with %d websites", *addr, len(data.Filter(filter).Websites)).Println()
+	if *token == "" {
+		Yellow("[!] No API token set; anyone who can reach this address can run searches").Println()
+	}
+	for _, warning := range network.secrets.Check(data) {
+		Yellow("[!] " + warning).Println()
+	}
+	log.Fatal(server.ListenAndServe())
+}
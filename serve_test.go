@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// apiTest is an API server searching websites on a local stand-in for the internet.
+type apiTest struct {
+	t       *testing.T
+	server  *httptest.Server
+	release chan struct{} // Closed to let searches of the slow website finish
+}
+
+// newAPITest starts an API server with the given token, job limit and website filter. Every API the searches use is served
+// locally: profiles exist only for alice, and the slow website answers once release is closed.
+func newAPITest(t *testing.T, token string, maxJobs int, filter gosearch.Filter) *apiTest {
+	t.Helper()
+	release := make(chan struct{})
+	internet := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/slow/"):
+			<-release
+		case strings.HasPrefix(r.URL.Path, "/users/") && r.URL.Path != "/users/alice":
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(internet.Close)
+
+	data := gosearch.Data{Websites: []gosearch.Website{
+		{Name: "Users", BaseURL: internet.URL + "/users/{}", ErrorType: "status_code", ErrorCode: 404},
+		{Name: "Slow", BaseURL: internet.URL + "/slow/{}", ErrorType: "status_code", ErrorCode: 404, Tags: []string{"slow"}},
+	}}
+	options := gosearch.Options{
+		Endpoints: gosearch.Endpoints{
+			HudsonRock: internet.URL + "/hudsonrock",
+			ProxyNova:  internet.URL + "/proxynova",
+			DNS:        internet.URL + "/dns",
+			RDAP:       internet.URL + "/rdap",
+		},
+		TLDs: []string{"test"},
+	}
+	server := httptest.NewServer(NewServer(data, filter, token, maxJobs, options).Handler())
+	t.Cleanup(server.Close)
+	t.Cleanup(func() {
+		select {
+		case <-release:
+		default:
+			close(release)
+		}
+	})
+	return &apiTest{t: t, server: server, release: release}
+}
+
+// do sends an API request with the token, if any, decoding the JSON response into v.
+func (a *apiTest) do(method, path, token, body string, v interface{}) int {
+	a.t.Helper()
+	req, _ := http.NewRequest(method, a.server.URL+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		json.NewDecoder(resp.Body).Decode(v)
+	}
+	return resp.StatusCode
+}
+
+// submit submits a job and returns its status.
+func (a *apiTest) submit(body string) JobStatus {
+	a.t.Helper()
+	var status JobStatus
+	if code := a.do(http.MethodPost, "/api/jobs", "", body, &status); code != http.StatusAccepted {
+		a.t.Fatalf("submitting %s: got status code %d", body, code)
+	}
+	return status
+}
+
+// wait polls a job until it has the wanted status.
+func (a *apiTest) wait(id, want string) JobStatus {
+	a.t.Helper()
+	var status JobStatus
+	for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		a.do(http.MethodGet, "/api/jobs/"+id, "", "", &status)
+		if status.Status == want {
+			return status
+		}
+	}
+	a.t.Fatalf("job %s is %s, want %s", id, status.Status, want)
+	return status
+}
+
+// sseEvent is an event read from a Server-Sent Events stream.
+type sseEvent struct {
+	id    int
+	event string
+}
+
+// events reads a job's event stream to its end, resuming after lastEventID if it is not empty.
+func (a *apiTest) events(id, lastEventID string) []sseEvent {
+	a.t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, a.server.URL+"/api/jobs/"+id+"/events", nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		a.t.Fatalf("got content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	var events []sseEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			n, _ := strconv.Atoi(strings.TrimPrefix(line, "id: "))
+			events = append(events, sseEvent{id: n})
+		case strings.HasPrefix(line, "event: "):
+			events[len(events)-1].event = strings.TrimPrefix(line, "event: ")
+		}
+	}
+	return events
+}
+
+func TestServerRunsJobs(t *testing.T) {
+	api := newAPITest(t, "", 1, gosearch.Filter{})
+
+	// With one job slot, a second job waits for the first
+	slow := api.submit(`{"username": "alice"}`)
+	api.wait(slow.ID, JobRunning)
+	queued := api.submit(`{"username": "bob", "exclude_tags": ["slow"]}`)
+	if queued.Status != JobQueued || queued.Websites != 1 {
+		t.Errorf("got second job %+v, want it queued with one website", queued)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if status := api.wait(queued.ID, JobQueued); status.SitesChecked != 0 {
+		t.Errorf("second job started while the first held the only slot: %+v", status)
+	}
+
+	var report gosearch.Report
+	if code := api.do(http.MethodGet, "/api/jobs/"+slow.ID+"/report", "", "", &report); code != http.StatusConflict {
+		t.Errorf("report of a running job: got status code %d, want %d", code, http.StatusConflict)
+	}
+
+	close(api.release)
+	done := api.wait(slow.ID, JobDone)
+	if done.SitesChecked != 2 || done.ProfilesFound != 2 || done.Finished == nil {
+		t.Errorf("got finished job %+v, want both websites checked and found", done)
+	}
+	if code := api.do(http.MethodGet, "/api/jobs/"+slow.ID+"/report", "", "", &report); code != http.StatusOK || report.Username != "alice" || len(report.Profiles) != 2 {
+		t.Errorf("got report %d %+v", code, report)
+	}
+	if status := api.wait(queued.ID, JobDone); status.ProfilesFound != 0 {
+		t.Errorf("got %+v, want no profiles for bob", status)
+	}
+
+	var jobs []JobStatus
+	api.do(http.MethodGet, "/api/jobs", "", "", &jobs)
+	if len(jobs) != 2 || jobs[0].ID != queued.ID {
+		t.Errorf("got jobs %+v, want both, newest first", jobs)
+	}
+}
+
+func TestServerRejectsJobs(t *testing.T) {
+	api := newAPITest(t, "", 1, gosearch.Filter{})
+	for _, body := range []string{
+		`not json`,
+		`{"username": " "}`,
+		`{"username": "alice", "sites": ["Unknown"]}`,
+		`{"username": "alice", "include_nsfw": true}`,
+	} {
+		if code := api.do(http.MethodPost, "/api/jobs", "", body, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: got status code %d, want %d", body, code, http.StatusBadRequest)
+		}
+	}
+	if code := api.do(http.MethodGet, "/api/jobs/missing", "", "", nil); code != http.StatusNotFound {
+		t.Errorf("unknown job: got status code %d, want %d", code, http.StatusNotFound)
+	}
+}
+
+func TestServerFilter(t *testing.T) {
+	// Jobs are checked against the websites the server searches, so they cannot ask for ones it excludes
+	api := newAPITest(t, "", 1, gosearch.Filter{ExcludeSites: []string{"Slow"}})
+	for _, body := range []string{
+		`{"username": "alice", "sites": ["Slow"]}`,
+		`{"username": "alice", "tags": ["slow"]}`,
+	} {
+		if code := api.do(http.MethodPost, "/api/jobs", "", body, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: got status code %d, want %d", body, code, http.StatusBadRequest)
+		}
+	}
+
+	job := api.submit(`{"username": "alice", "exclude_tags": ["slow"]}`)
+	if job.Websites != 1 || len(job.Warnings) != 1 || !strings.Contains(job.Warnings[0], "slow") {
+		t.Errorf("got job %+v, want one website and the exclusion reported as having no effect", job)
+	}
+	if status := api.wait(job.ID, JobDone); status.ProfilesFound != 1 {
+		t.Errorf("got %+v, want only the served website searched", status)
+	}
+}
+
+func TestServerEventsResume(t *testing.T) {
+	api := newAPITest(t, "", 1, gosearch.Filter{})
+	close(api.release)
+	job := api.submit(`{"username": "alice"}`)
+	api.wait(job.ID, JobDone)
+
+	all := api.events(job.ID, "")
+	if len(all) == 0 || all[0].id != 0 || all[len(all)-1].event != "done" {
+		t.Fatalf("got events %+v, want ids from 0 ending with done", all)
+	}
+	results := 0
+	for i, event := range all {
+		if event.id != i {
+			t.Errorf("event %d has id %d", i, event.id)
+		}
+		if event.event == "result" {
+			results++
+		}
+	}
+	if results != 2 {
+		t.Errorf("got %d result events, want 2", results)
+	}
+
+	// Reconnecting with Last-Event-ID resumes after that event
+	resumed := api.events(job.ID, strconv.Itoa(all[2].id))
+	if len(resumed) != len(all)-3 || resumed[0] != all[3] {
+		t.Errorf("got resumed events %+v, want %+v", resumed, all[3:])
+	}
+	if resumed := api.events(job.ID, "not-a-number"); len(resumed) != len(all) {
+		t.Errorf("invalid Last-Event-ID: got %d events, want all %d", len(resumed), len(all))
+	}
+}
+
+func TestServerAuthentication(t *testing.T) {
+	api := newAPITest(t, "secret", 1, gosearch.Filter{})
+	tests := []struct {
+		token string
+		code  int
+	}{
+		{"", http.StatusUnauthorized},
+		{"wrong", http.StatusUnauthorized},
+		{"secret", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := api.do(http.MethodGet, "/api/jobs", tt.token, "", nil); code != tt.code {
+			t.Errorf("token %q: got status code %d, want %d", tt.token, code, tt.code)
+		}
+	}
+}
+
+func TestServerPrunesJobs(t *testing.T) {
+	server := NewServer(gosearch.Data{}, gosearch.Filter{}, "", 1, gosearch.Options{})
+	expired := time.Now().Add(-jobRetention - time.Minute)
+	recent := time.Now().Add(-time.Minute)
+
+	jobs := map[string]*time.Time{"expired": &expired, "recent": &recent, "running": nil}
+	for id, finished := range jobs {
+		job := NewJob(id, JobOptions{Username: "alice"}, 0)
+		job.status.Finished = finished
+		server.jobs[id] = job
+	}
+
+	server.mu.Lock()
+	server.pruneJobs()
+	server.mu.Unlock()
+	if _, ok := server.jobs["expired"]; ok || len(server.jobs) != 2 {
+		t.Errorf("got jobs %v, want only the recent and running ones", server.jobs)
+	}
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bytedance/sonic"
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// Output formats supported by --format.
+const (
+	FormatText   = "text"   // Plain text file, one finding per line
+	FormatJSON   = "json"   // JSON report written when the run finishes
+	FormatCSV    = "csv"    // CSV file with one row per profile or domain
+	FormatStdout = "stdout" // Terminal output only; no file is written
+)
+
+// DefaultOutputTemplate is the default output filename template.
+const DefaultOutputTemplate = "{username}"
+
+// Sink writes search results to an output destination.
+// Write errors are reported once and do not stop the search; Close returns the first error encountered.
+type Sink interface {
+	gosearch.Handler
+	Close() error // Flushes and closes the output
+}
+
+// OutputPath builds an output file path from a directory, a filename template and a format extension.
+// The template may contain {username}, {date} and {time} placeholders.
+func OutputPath(dir, template, username, ext string) string {
+	now := time.Now()
+
+	// Keep usernames from escaping the output directory
+	safeUsername := strings.NewReplacer("/", "_", "\\", "_").Replace(username)
+
+	name := strings.NewReplacer(
+		"{username}", safeUsername,
+		"{date}", now.Format("2006-01-02"),
+		"{time}", now.Format("150405"),
+	).Replace(template)
+
+	if filepath.Ext(name) != ext {
+		name += ext
+	}
+	return filepath.Join(dir, name)
+}
+
+// NewSinks creates a sink for every comma-separated format, writing files to dir named after template.
+func NewSinks(formats, dir, template, username string) ([]Sink, error) {
+	var sinks []Sink
+	for _, format := range strings.Split(formats, ",") {
+		format = strings.TrimSpace(format)
+
+		var sink Sink
+		var err error
+		switch format {
+		case FormatText:
+			sink, err = NewTextSink(OutputPath(dir, template, username, ".txt"), username)
+		case FormatJSON:
+			sink = NewJSONSink(OutputPath(dir, template, username, ".json"))
+		case FormatCSV:
+			sink, err = NewCSVSink(OutputPath(dir, template, username, ".csv"))
+		case FormatStdout:
+			continue
+		default:
+			err = fmt.Errorf("unknown output format %q (expected text, json, csv or stdout)", format)
+		}
+		if err != nil {
+			for _, s := range sinks {
+				s.Close()
+			}
+			return nil, err
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+// createOutputFile creates (or truncates) an output file, creating its directory if needed.
+func createOutputFile(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating output directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating output file: %w", err)
+	}
+	return f, nil
+}
+
+// sinkError records the first write error of a sink and reports it to the terminal.
+type sinkError struct {
+	path string
+	err  error
+}
+
+// fail records err if it is the first error, printing it without stopping the search.
+func (e *sinkError) fail(err error) {
+	if err == nil || e.err != nil {
+		return
+	}
+	e.err = err
+	Redf("Error writing %s:", e.path).Print()
+	White(" " + err.Error()).Println()
+}
+
+// TextSink writes search results to a plain text file.
+type TextSink struct {
+	sinkError
+	username string
+	file     *os.File
+	w        *bufio.Writer
+}
+
+// NewTextSink creates a text sink writing to path.
+func NewTextSink(path, username string) (*TextSink, error) {
+	f, err := createOutputFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &TextSink{
+		sinkError: sinkError{path: path},
+		username:  username,
+		file:      f,
+		w:         bufio.NewWriter(f),
+	}, nil
+}
+
+// writeLine writes content followed by a newline unless it already ends with one.
+func (s *TextSink) writeLine(content string) {
+	if s.err != nil {
+		return
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	_, err := s.w.WriteString(content)
+	s.fail(err)
+}
+
+// StageStarted separates breach database sections in the file.
+func (s *TextSink) StageStarted(stage string) {
+	switch stage {
+	case gosearch.StageHudsonRock, gosearch.StageProxyNova:
+		s.writeLine(strings.Repeat("⎯", 85))
+	}
+}
+
+// SiteResult writes a found or unverified profile URL.
+func (s *TextSink) SiteResult(result gosearch.Result) {
+	switch result.Status {
+	case gosearch.StatusFound:
+		s.writeLine(result.URL + resultLabel(result))
+	case gosearch.StatusUnverified:
+		s.writeLine("[?] " + result.URL + resultLabel(result))
+	}
+}
+
+// StageFinished writes the outcome of a completed search stage.
+func (s *TextSink) StageFinished(stage string, report *gosearch.Report, err error) {
+	if err != nil && stage != gosearch.StageDomains {
+		return
+	}
+
+	switch stage {
+	case gosearch.StageHudsonRock:
+		s.writeLine(FormatHudsonRock(*report.HudsonRock))
+	case gosearch.StageBreachDirectory:
+		if len(report.BreachDirectory) == 0 {
+			s.writeLine("[-] No breaches found on Breach Directory for: " + s.username)
+		}
+		for _, breach := range report.BreachDirectory {
+			s.writeLine("[+] Password: " + breach.Password)
+			s.writeLine("[+] Source: " + breach.Sources)
+		}
+	case gosearch.StageProxyNova:
+		for _, element := range report.ProxyNova.Lines {
+			parts := strings.Split(element, ":")
+			if len(parts) == 2 {
+				s.writeLine("[+] Email: " + parts[0] + "\n" + "[+] Password: " + parts[1] + "\n\n")
+			}
+		}
+	case gosearch.StageDomains:
+		for _, domain := range report.DomainResults {
+			if domain.Found() {
+				s.writeLine("[+] " + domain.Status + ": " + domain.Domain + " -> " + domain.FinalURL)
+			} else if domain.Parked != "" {
+				s.writeLine("[?] " + domain.Status + ": " + domain.Domain + " (" + domain.Parked + ")")
+			} else if domain.Status == gosearch.DomainParked {
+				s.writeLine("[?] " + domain.Status + ": " + domain.Domain)
+			}
+		}
+		if len(report.Domains) > 0 {
+			s.writeLine("[+] Found " + strconv.Itoa(len(report.Domains)) + " domains with the username: " + s.username)
+		} else {
+			s.writeLine("[-] No domains found with the username: " + s.username)
+		}
+	}
+}
+
+// RunFinished writes the number of profiles found and the time taken.
+func (s *TextSink) RunFinished(report *gosearch.Report) {
+	s.writeLine(":: Number of profiles found              : " + strconv.Itoa(len(report.Profiles)))
+	s.writeLine(":: Total time taken                      : " + report.Elapsed)
+}
+
+// Close flushes and closes the file.
+func (s *TextSink) Close() error {
+	s.fail(s.w.Flush())
+	s.fail(s.file.Close())
+	return s.err
+}
+
+// JSONSink writes the complete search report as JSON when the run finishes.
+type JSONSink struct {
+	sinkError
+}
+
+// NewJSONSink creates a JSON sink writing to path.
+func NewJSONSink(path string) *JSONSink {
+	return &JSONSink{sinkError: sinkError{path: path}}
+}
+
+// StageStarted does nothing; the report is written when the run finishes.
+func (s *JSONSink) StageStarted(stage string) {}
+
+// SiteResult does nothing; the report is written when the run finishes.
+func (s *JSONSink) SiteResult(result gosearch.Result) {}
+
+// StageFinished does nothing; the report is written when the run finishes.
+func (s *JSONSink) StageFinished(stage string, report *gosearch.Report, err error) {}
+
+// RunFinished writes the report.
+func (s *JSONSink) RunFinished(report *gosearch.Report) {
+	data, err := sonic.ConfigStd.MarshalIndent(report, "", "  ")
+	if err != nil {
+		s.fail(fmt.Errorf("error encoding report: %w", err))
+		return
+	}
+
+	f, err := createOutputFile(s.path)
+	if err != nil {
+		s.fail(err)
+		return
+	}
+	_, err = f.Write(append(data, '\n'))
+	s.fail(err)
+	s.fail(f.Close())
+}
+
+// Close returns the first write error, if any.
+func (s *JSONSink) Close() error {
+	return s.err
+}
+
+// CSVSink writes found profiles and domains as CSV rows.
+type CSVSink struct {
+	sinkError
+	file *os.File
+	w    *csv.Writer
+}
+
+// NewCSVSink creates a CSV sink writing to path.
+func NewCSVSink(path string) (*CSVSink, error) {
+	f, err := createOutputFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s := &CSVSink{
+		sinkError: sinkError{path: path},
+		file:      f,
+		w:         csv.NewWriter(f),
+	}
+	s.write("username", "website", "url", "status", "nsfw", "catalog")
+	return s, nil
+}
+
+// write writes a single CSV row.
+func (s *CSVSink) write(fields ...string) {
+	if s.err != nil {
+		return
+	}
+	s.fail(s.w.Write(fields))
+}
+
+// StageStarted does nothing.
+func (s *CSVSink) StageStarted(stage string) {}
+
+// SiteResult writes a row for a found or unverified profile.
+func (s *CSVSink) SiteResult(result gosearch.Result) {
+	if result.Profile() {
+		s.write(result.Username, result.Website, result.URL, result.Status, strconv.FormatBool(result.NSFW), result.Catalog)
+	}
+}
+
+// StageFinished writes a row for every registered domain, with its domain status.
+func (s *CSVSink) StageFinished(stage string, report *gosearch.Report, err error) {
+	if stage != gosearch.StageDomains {
+		return
+	}
+	for _, domain := range report.DomainResults {
+		if domain.Status == gosearch.DomainUnregistered || domain.Status == gosearch.DomainUnknown {
+			continue
+		}
+		s.write(report.Username, "Domain", domain.Domain, domain.Status, "false", "")
+	}
+}
+
+// RunFinished does nothing.
+func (s *CSVSink) RunFinished(report *gosearch.Report) {}
+
+// Close flushes and closes the file.
+func (s *CSVSink) Close() error {
+	s.w.Flush()
+	s.fail(s.w.Error())
+	s.fail(s.file.Close())
+	return s.err
+}
+
+// FormatHudsonRock formats HudsonRock's findings as plain text.
+func FormatHudsonRock(response gosearch.HudsonRockResponse) string {
+	// Check if no compromises were found
+	if !response.Compromised() {
+		return ":: No info-stealer association found"
+	}
+
+	var content strings.Builder
+
+	// Process each stealer entry
+	for i, stealer := range response.Stealers {
+		// Format antiviruses
+		var avs string
+		switch v := stealer.Antiviruses.(type) {
+		case string:
+			avs = v
+		case []interface{}:
+			parts := make([]string, len(v))
+			for i, av := range v {
+				parts[i] = fmt.Sprint(av)
+			}
+			avs = strings.Join(parts, ", ")
+		}
+
+		content.WriteString(fmt.Sprintf("[-] Stealer #%d\n", i+1))
+		content.WriteString(fmt.Sprintf(":: Family: %s\n", stealer.StealerFamily))
+		content.WriteString(fmt.Sprintf(":: Date: %s\n", stealer.DateCompromised))
+		content.WriteString(fmt.Sprintf(":: Computer: %s\n", stealer.ComputerName))
+		content.WriteString(fmt.Sprintf(":: OS: %s\n", stealer.OperatingSystem))
+		content.WriteString(fmt.Sprintf(":: Path: %s\n", stealer.MalwarePath))
+		content.WriteString(fmt.Sprintf(":: AV: %s\n", avs))
+		content.WriteString(fmt.Sprintf(":: IP: %s\n", stealer.IP))
+
+		content.WriteString(":: Passwords:\n")
+		for _, p := range stealer.TopPasswords {
+			content.WriteString(fmt.Sprintf("   %s\n", p))
+		}
+
+		content.WriteString(":: Logins:\n")
+		for _, l := range stealer.TopLogins {
+			content.WriteString(fmt.Sprintf("   %s\n", l))
+		}
+		content.WriteString("\n")
+	}
+
+	return content.String()
+}
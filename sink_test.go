@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tkerby/gosearch/pkg/gosearch"
+)
+
+// sinkReport is the report of the search run through the sinks in the tests.
+var sinkReport = &gosearch.Report{
+	Username: "alice",
+	Websites: 3,
+	Profiles: []gosearch.Result{
+		testFinding,
+		{Username: "alice", Website: "OnlyFans", URL: "https://onlyfans.com/alice", Status: gosearch.StatusUnverified, NSFW: true, Catalog: "local"},
+	},
+	Domains: []string{"alice.com"},
+	DomainResults: []gosearch.DomainResult{
+		{Domain: "alice.com", Status: gosearch.DomainLive, FinalURL: "https://alice.com/"},
+		{Domain: "alice.net", Status: gosearch.DomainParked, Parked: "for sale"},
+		{Domain: "alice.org", Status: gosearch.DomainUnregistered},
+	},
+	Elapsed: "1s",
+}
+
+// runSink runs the search's events through the sink and closes it.
+func runSink(t *testing.T, sink Sink) {
+	t.Helper()
+	sink.StageStarted(gosearch.StageSites)
+	for _, result := range sinkReport.Profiles {
+		sink.SiteResult(result)
+	}
+	sink.SiteResult(gosearch.Result{Username: "alice", Website: "GitLab", URL: "https://gitlab.com/alice", Status: gosearch.StatusNotFound})
+	sink.StageFinished(gosearch.StageSites, sinkReport, nil)
+	sink.StageStarted(gosearch.StageDomains)
+	sink.StageFinished(gosearch.StageDomains, sinkReport, nil)
+	sink.RunFinished(sinkReport)
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readOutput returns the content of an output file.
+func readOutput(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestTextSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alice.txt")
+	sink, err := NewTextSink(path, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runSink(t, sink)
+
+	want := []string{
+		"https://github.com/alice",
+		"[?] https://onlyfans.com/alice [NSFW] (catalog: local)",
+		"[+] registered-live: alice.com -> https://alice.com/",
+		"[?] registered-parked: alice.net (for sale)",
+		"[+] Found 1 domains with the username: alice",
+		":: Number of profiles found              : 2",
+		":: Total time taken                      : 1s",
+	}
+	if got := strings.Split(strings.TrimSuffix(readOutput(t, path), "\n"), "\n"); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got lines\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestJSONSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports", "alice.json")
+	runSink(t, NewJSONSink(path))
+
+	var report gosearch.Report
+	if err := json.Unmarshal([]byte(readOutput(t, path)), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.Username != "alice" || len(report.Profiles) != 2 || report.Profiles[1].URL != "https://onlyfans.com/alice" || len(report.DomainResults) != 3 {
+		t.Errorf("got report %+v", report)
+	}
+}
+
+func TestCSVSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alice.csv")
+	sink, err := NewCSVSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runSink(t, sink)
+
+	rows, err := csv.NewReader(strings.NewReader(readOutput(t, path))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"username", "website", "url", "status", "nsfw", "catalog"},
+		{"alice", "GitHub", "https://github.com/alice", gosearch.StatusFound, "false", ""},
+		{"alice", "OnlyFans", "https://onlyfans.com/alice", gosearch.StatusUnverified, "true", "local"},
+		{"alice", "Domain", "alice.com", gosearch.DomainLive, "false", ""},
+		{"alice", "Domain", "alice.net", gosearch.DomainParked, "false", ""},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got rows %q, want %q", rows, want)
+	}
+	for i := range want {
+		if strings.Join(rows[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("row %d = %q, want %q", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestNewSinks(t *testing.T) {
+	dir := t.TempDir()
+	sinks, err := NewSinks("text, json,csv,stdout", dir, DefaultOutputTemplate, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sinks) != 3 {
+		t.Fatalf("got %d sinks, want one per file format", len(sinks))
+	}
+	for _, sink := range sinks {
+		runSink(t, sink)
+	}
+	for _, name := range []string{"alice.txt", "alice.json", "alice.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+
+	if _, err := NewSinks("text,xml", t.TempDir(), DefaultOutputTemplate, "alice"); err == nil || !strings.Contains(err.Error(), "xml") {
+		t.Errorf("got error %v, want the unknown format reported", err)
+	}
+}
+
+func TestOutputPath(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		template string
+		username string
+		want     string
+	}{
+		{DefaultOutputTemplate, "alice", "alice.txt"},
+		{"{username}.txt", "alice", "alice.txt"},
+		{"search-{username}", "alice", "search-alice.txt"},
+		{DefaultOutputTemplate, "../../evil", ".._.._evil.txt"},
+		{DefaultOutputTemplate, `..\evil`, ".._evil.txt"},
+		{DefaultOutputTemplate, "/etc/passwd", "_etc_passwd.txt"},
+		{DefaultOutputTemplate, "..", "...txt"},
+	}
+	for _, tt := range tests {
+		got := OutputPath(dir, tt.template, tt.username, ".txt")
+		if got != filepath.Join(dir, tt.want) {
+			t.Errorf("OutputPath(%q, %q) = %s, want %s", tt.template, tt.username, got, tt.want)
+		}
+	}
+
+	// A username trying to escape the output directory still has its files written inside it
+	sinks, err := NewSinks("text,json,csv", dir, DefaultOutputTemplate, "../../evil")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, sink := range sinks {
+		runSink(t, sink)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if want := ".._.._evil.csv .._.._evil.json .._.._evil.txt"; strings.Join(names, " ") != want {
+		t.Errorf("got files %q in the output directory, want %s", names, want)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "..", "evil.txt")); err == nil {
+		t.Error("a file was written outside the output directory")
+	}
+}
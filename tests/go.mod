@@ -1,17 +0,0 @@
-module tests
-
-go 1.23.3
-
-require (
-	github.com/andybalholm/brotli v1.1.1
-	github.com/bytedance/sonic v1.12.8
-)
-
-require (
-	github.com/bytedance/sonic/loader v0.2.3 // indirect
-	github.com/cloudwego/base64x v0.1.5 // indirect
-	github.com/klauspost/cpuid/v2 v2.2.9 // indirect
-	github.com/twitchyliquid64/golang-asm v0.15.1 // indirect
-	golang.org/x/arch v0.14.0 // indirect
-	golang.org/x/sys v0.30.0 // indirect
-)